@@ -31,14 +31,13 @@ resource "edge_value" "demo_bool" {
   description     = "demo bool value"
   default_variant = "off"
 
-  boolean_value {
-    variant = "on"
-    value   = true
-  }
-
-  boolean_value {
-    variant = "off"
-    value   = false
+  variants = {
+    on = {
+      boolean_value = true
+    }
+    off = {
+      boolean_value = false
+    }
   }
 
   targeting {
@@ -60,14 +59,13 @@ resource "edge_value" "demo_string" {
   description     = "demo string value"
   default_variant = "string01"
 
-  string_value {
-    variant = "string01"
-    value   = "string01"
-  }
-
-  string_value {
-    variant = "string02"
-    value   = "string02"
+  variants = {
+    string01 = {
+      string_value = "string01"
+    }
+    string02 = {
+      string_value = "string02"
+    }
   }
 
   targeting {
@@ -89,33 +87,36 @@ resource "edge_value" "demo_json" {
   description     = "demo json value"
   default_variant = "json01"
 
-  json_value {
-    variant = "json01"
-    value   = jsonencode({
-      "items" : [
-        {"viewable": true, "content": "content1"},
-        {"viewable": true, "content": "content2"},
-        {"viewable": false, "content": "content3"}
+  variants = {
+    json01 = {
+      json_value = jsonencode({
+        "items" : [
+          {"viewable": true, "content": "content1"},
+          {"viewable": true, "content": "content2"},
+          {"viewable": false, "content": "content3"}
+        ]
+      })
+      transform = [
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"
+        },
       ]
-    })
-    transform {
-      spec = "cel"
-      expr = "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"
     }
-  }
-
-  json_value {
-    variant = "json01"
-    value   = jsonencode({
-      "items" : [
-        {"viewable": true, "content": "content1"},
-        {"viewable": false, "content": "content2"},
-        {"viewable": false, "content": "content3"}
+    json02 = {
+      json_value = jsonencode({
+        "items" : [
+          {"viewable": true, "content": "content1"},
+          {"viewable": false, "content": "content2"},
+          {"viewable": false, "content": "content3"}
+        ]
+      })
+      transform = [
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"
+        },
       ]
-    })
-    transform {
-      spec = "cel"
-      expr = "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"
     }
   }
 
@@ -126,8 +127,118 @@ resource "edge_value" "demo_json" {
   }
 
   targeting {
-    variant = "json01"
+    variant = "json02"
     spec    = "cel"
     expr    = "userId == 'XXX'"
   }
 }
+
+resource "edge_value" "demo_config" {
+  value_id        = "demo-config-value"
+  enabled         = true
+  description     = "demo config value declared with native values"
+  default_variant = "standard"
+
+  variant_values = {
+    standard = {
+      page_size = 20
+      features  = ["search"]
+    }
+    beta = {
+      page_size = 50
+      features  = ["search", "recommendations"]
+    }
+  }
+
+  targeting {
+    variant = "beta"
+    spec    = "cel"
+    expr    = "env == 'dev'"
+  }
+}
+
+resource "edge_value" "demo_number" {
+  value_id        = "demo-number-value"
+  enabled         = true
+  description     = "demo sampling rate"
+  default_variant = "low"
+
+  variants = {
+    low = {
+      number_value = 0.01
+    }
+    high = {
+      number_value = 0.25
+    }
+  }
+
+  targeting {
+    variant = "high"
+    spec    = "cel"
+    expr    = "env == 'dev'"
+  }
+}
+
+data "edge_value" "shared_config" {
+  value_id = "demo-json-value"
+}
+
+data "edge_values" "json" {
+  id_prefix    = "demo-"
+  variant_type = "json"
+}
+
+resource "edge_boolean_value" "demo_typed_bool" {
+  value_id        = "demo-typed-bool-value"
+  enabled         = true
+  default_variant = "off"
+
+  variants = {
+    on  = true
+    off = false
+  }
+
+  targeting {
+    variant = "on"
+    spec    = "cel"
+    expr    = "env == 'dev'"
+  }
+}
+
+# Named targeting rules of a shared value can be added by other modules. The owning
+# edge_value keeps them next to its own targeting rules.
+resource "edge_value" "demo_shared" {
+  value_id        = "demo-shared-value"
+  enabled         = true
+  default_variant = "off"
+
+  variants = {
+    on = {
+      boolean_value = true
+    }
+    off = {
+      boolean_value = false
+    }
+  }
+
+  targeting {
+    variant = "on"
+    spec    = "cel"
+    expr    = "env == 'dev'"
+  }
+}
+
+resource "edge_value_targeting_rule" "demo_tenant" {
+  value_id = edge_value.demo_shared.value_id
+  name     = "tenant-demo"
+  variant  = "on"
+  expr     = "tenant == 'demo'"
+  priority = 0
+}
+
+# An existing edge_value can be migrated without re-creating it:
+#
+# moved {
+#   from = edge_value.demo_bool
+#   to   = edge_boolean_value.demo_bool
+# }
@@ -0,0 +1,247 @@
+package edgeclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	headerKeyID       = "X-API-KEY-ID"
+	headerKey         = "X-API-KEY"
+	headerUA          = "User-Agent"
+	headerContentType = "Content-Type"
+	headerIfMatch     = "If-Match"
+)
+
+const (
+	applicationJSON = "application/json"
+)
+
+const (
+	pathGetValue    = "/service.Value/Get"
+	pathCreateValue = "/service.Value/Create"
+	pathUpdateValue = "/service.Value/Update"
+	pathDeleteValue = "/service.Value/Delete"
+	pathListValues  = "/service.Value/List"
+)
+
+// Client is the Edge API used by the provider.
+type Client interface {
+	GetValue(ctx context.Context, id string) (*model.Value, error)
+	CreateValue(ctx context.Context, value *model.Value) (*model.Value, error)
+	// UpdateValue replaces value. A non-empty revision is the update time the caller last saw:
+	// the update is then rejected with ErrConflict if the value has changed since.
+	// A retried attempt that conflicts with an earlier attempt of the same update succeeds.
+	UpdateValue(ctx context.Context, value *model.Value, revision string) (*model.Value, error)
+	DeleteValue(ctx context.Context, id string) error
+	ListValues(ctx context.Context, req *model.ListValuesRequest) (*model.ListValuesResponse, error)
+}
+
+// Config configures the HTTP implementation of Client.
+type Config struct {
+	Endpoint  string
+	APIKeyID  string
+	APIKey    string
+	UserAgent string
+
+	// HTTPClient performs the underlying requests. Retries are layered on top of it.
+	// Defaults to a pooled client when nil.
+	HTTPClient *http.Client
+
+	// RequestTimeout bounds every single HTTP attempt, excluding the wait for a slot under
+	// MaxConcurrentRequests. Zero means no timeout.
+	RequestTimeout time.Duration
+
+	// Retry is the retry policy. DefaultRetryPolicy is used when nil.
+	Retry *RetryPolicy
+
+	// MaxConcurrentRequests caps the number of HTTP requests in flight. Zero means no limit.
+	MaxConcurrentRequests int
+}
+
+var _ Client = &client{}
+
+type client struct {
+	locks    *KeyedMutex
+	ua       string
+	keyID    string
+	key      string
+	endpoint string
+	client   *retryablehttp.Client
+}
+
+// New returns a Client talking to the Edge API described by cfg.
+func New(cfg Config) Client {
+	policy := DefaultRetryPolicy()
+	if cfg.Retry != nil {
+		policy = *cfg.Retry
+	}
+
+	rc := retryablehttp.NewClient()
+	rc.RetryMax = max(policy.MaxAttempts-1, 0)
+	rc.RetryWaitMin = policy.MinWait
+	rc.RetryWaitMax = policy.MaxWait
+	rc.CheckRetry = policy.checkRetry
+	rc.Backoff = policy.backoff
+	rc.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	rc.RequestLogHook = countAttempt
+	if cfg.HTTPClient != nil {
+		hc := *cfg.HTTPClient
+		rc.HTTPClient = &hc
+	}
+	if cfg.MaxConcurrentRequests > 0 {
+		// The transport times the attempts itself, after they wait for a slot.
+		rc.HTTPClient.Transport = newLimitTransport(rc.HTTPClient.Transport, cfg.MaxConcurrentRequests, cfg.RequestTimeout)
+	} else if cfg.RequestTimeout > 0 {
+		rc.HTTPClient.Timeout = cfg.RequestTimeout
+	}
+	return &client{
+		locks:    NewKeyedMutex(),
+		ua:       cfg.UserAgent,
+		keyID:    cfg.APIKeyID,
+		key:      cfg.APIKey,
+		endpoint: cfg.Endpoint,
+		client:   rc,
+	}
+}
+
+func (c *client) GetValue(ctx context.Context, id string) (*model.Value, error) {
+	value := new(model.Value)
+	if err := c.call(ctx, pathGetValue, &model.GetValueRequest{ID: id}, value, ""); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c *client) CreateValue(ctx context.Context, value *model.Value) (*model.Value, error) {
+	v := new(model.Value)
+	if err := c.call(withNonIdempotent(ctx), pathCreateValue, value, v, value.ID); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *client) UpdateValue(ctx context.Context, value *model.Value, revision string) (*model.Value, error) {
+	v := new(model.Value)
+	var attempts int
+	err := c.call(withAttempts(withRevision(ctx, revision), &attempts), pathUpdateValue, value, v, value.ID)
+	if IsConflict(err) && revision != "" && attempts > 1 {
+		// An earlier attempt may have been applied before it failed, such as on a timeout, in
+		// which case the retry conflicts with it. The update succeeded if the value is as sent.
+		if current, gerr := c.GetValue(ctx, value.ID); gerr == nil && sameValue(value, current) {
+			return current, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *client) DeleteValue(ctx context.Context, id string) error {
+	err := c.call(ctx, pathDeleteValue, &model.DeleteValueRequest{ID: id}, nil, id)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *client) ListValues(ctx context.Context, req *model.ListValuesRequest) (*model.ListValuesResponse, error) {
+	res := new(model.ListValuesResponse)
+	if err := c.call(ctx, pathListValues, req, res, ""); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// call posts in as JSON to the given procedure and decodes the response into out.
+// A nil out discards the response body. Calls sharing a non-empty lockID never run concurrently.
+func (c *client) call(ctx context.Context, path string, in, out any, lockID string) error {
+	u, err := url.JoinPath(c.endpoint, path)
+	if err != nil {
+		return err
+	}
+
+	j, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(j))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, lockID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return parseError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	// Numbers are kept as json.Number so that JSON variant values survive a round trip.
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	return dec.Decode(out)
+}
+
+func (c *client) do(req *retryablehttp.Request, lockID string) (*http.Response, error) {
+	if lockID != "" {
+		unlock := c.locks.Lock(lockID)
+		defer unlock()
+	}
+	req.Header.Set(headerKeyID, c.keyID)
+	req.Header.Set(headerKey, c.key)
+	req.Header.Set(headerUA, c.ua)
+	req.Header.Set(headerContentType, applicationJSON)
+	if rev := revisionFrom(req.Context()); rev != "" {
+		req.Header.Set(headerIfMatch, strconv.Quote(rev))
+	}
+	return c.client.Do(req)
+}
+
+// sameValue reports whether a and b hold the same Value, whatever their revisions.
+func sameValue(a, b *model.Value) bool {
+	x, y := *a, *b
+	x.CreateTime, x.UpdateTime = "", ""
+	y.CreateTime, y.UpdateTime = "", ""
+	jx, err := json.Marshal(x)
+	if err != nil {
+		return false
+	}
+	jy, err := json.Marshal(y)
+	if err != nil {
+		return false
+	}
+	return jsontypes.Equivalent(string(jx), string(jy))
+}
+
+type revisionKey struct{}
+
+// withRevision conditions requests made with ctx on the value still being at revision. An empty
+// revision leaves them unconditional.
+func withRevision(ctx context.Context, revision string) context.Context {
+	if revision == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, revisionKey{}, revision)
+}
+
+func revisionFrom(ctx context.Context) string {
+	rev, _ := ctx.Value(revisionKey{}).(string)
+	return rev
+}
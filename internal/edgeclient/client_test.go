@@ -0,0 +1,356 @@
+package edgeclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/jarcoal/httpmock"
+)
+
+const testEndpoint = "http://localhost:8018"
+
+func newTestClient(mock *httpmock.MockTransport) Client {
+	return New(Config{
+		Endpoint:  testEndpoint,
+		APIKeyID:  "test_key_id",
+		APIKey:    "test_key",
+		UserAgent: "test",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+}
+
+func TestClient_GetValue(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Get",
+		func(req *http.Request) (*http.Response, error) {
+			if got := req.Header.Get(headerKeyID); got != "test_key_id" {
+				t.Errorf("expected %s header to be test_key_id, but got %q", headerKeyID, got)
+			}
+			if got := req.Header.Get(headerKey); got != "test_key" {
+				t.Errorf("expected %s header to be test_key, but got %q", headerKey, got)
+			}
+			return httpmock.NewStringResponse(200, `{"id":"test","enabled":true,"defaultVariant":"on"}`), nil
+		},
+	)
+
+	got, err := newTestClient(mock).GetValue(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != "test" || !got.Enabled || got.DefaultVariant != "on" {
+		t.Fatalf("unexpected value: %+v", got)
+	}
+}
+
+func TestClient_GetValue_NotFound(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Get",
+		httpmock.NewStringResponder(http.StatusNotFound, `{"code":"not_found","message":"value test not found"}`),
+	)
+
+	got, err := newTestClient(mock).GetValue(context.Background(), "test")
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found error, but got %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected nil value, but got %+v", got)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+		code   Code
+		actor  string
+	}{
+		{
+			name:   "connect not found",
+			status: http.StatusNotFound,
+			body:   `{"code":"not_found","message":"value test not found"}`,
+			want:   ErrNotFound,
+			code:   CodeNotFound,
+		},
+		{
+			name:   "connect already exists",
+			status: http.StatusConflict,
+			body:   `{"code":"already_exists","message":"value test already exists"}`,
+			want:   ErrAlreadyExists,
+			code:   CodeAlreadyExists,
+		},
+		{
+			name:   "connect failed precondition",
+			status: http.StatusBadRequest,
+			body:   `{"code":"failed_precondition","message":"value test was updated at 1682089734"}`,
+			want:   ErrConflict,
+			code:   CodeFailedPrecondition,
+		},
+		{
+			name:   "connect aborted",
+			status: http.StatusConflict,
+			body:   `{"code":"aborted"}`,
+			want:   ErrConflict,
+			code:   CodeAborted,
+		},
+		{
+			name:   "connect failed precondition with actor",
+			status: http.StatusBadRequest,
+			body:   `{"code":"failed_precondition","details":[{"type":"edge.v1.ConflictInfo","value":"","debug":{"actor":"someone@example.com"}}]}`,
+			want:   ErrConflict,
+			code:   CodeFailedPrecondition,
+			actor:  "someone@example.com",
+		},
+		{
+			name:   "plain text precondition failed",
+			status: http.StatusPreconditionFailed,
+			body:   `precondition failed`,
+			want:   ErrConflict,
+			code:   CodeFailedPrecondition,
+		},
+		{
+			name:   "connect invalid argument",
+			status: http.StatusBadRequest,
+			body:   `{"code":"invalid_argument","message":"invalid expr"}`,
+			want:   ErrInvalidArgument,
+			code:   CodeInvalidArgument,
+		},
+		{
+			name:   "connect permission denied",
+			status: http.StatusForbidden,
+			body:   `{"code":"permission_denied"}`,
+			want:   ErrPermissionDenied,
+			code:   CodePermissionDenied,
+		},
+		{
+			name:   "plain text unavailable",
+			status: http.StatusServiceUnavailable,
+			body:   `upstream connect error`,
+			want:   ErrUnavailable,
+			code:   CodeUnavailable,
+		},
+		{
+			name:   "plain text not found",
+			status: http.StatusNotFound,
+			body:   `404 page not found`,
+			want:   ErrNotFound,
+			code:   CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mock := httpmock.NewMockTransport()
+			mock.RegisterResponder(
+				http.MethodPost,
+				testEndpoint+"/service.Value/Create",
+				httpmock.NewStringResponder(tt.status, tt.body),
+			)
+			c := newTestClient(mock).(*client)
+			c.client.RetryMax = 0
+
+			_, err := c.CreateValue(context.Background(), &model.Value{ID: "test"})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, but got %v", tt.want, err)
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("expected *Error, but got %T", err)
+			}
+			if e.Code != tt.code || e.StatusCode != tt.status {
+				t.Fatalf("expected %s (%d), but got %s (%d)", tt.code, tt.status, e.Code, e.StatusCode)
+			}
+			if e.Actor != tt.actor {
+				t.Fatalf("expected actor %q, but got %q", tt.actor, e.Actor)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateValue_Revision(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		revision string
+		want     string
+	}{
+		{
+			revision: "1682089734",
+			want:     `"1682089734"`,
+		},
+		{
+			revision: "",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.revision, func(t *testing.T) {
+			t.Parallel()
+			mock := httpmock.NewMockTransport()
+			mock.RegisterResponder(
+				http.MethodPost,
+				testEndpoint+"/service.Value/Update",
+				func(req *http.Request) (*http.Response, error) {
+					if got := req.Header.Get(headerIfMatch); got != tt.want {
+						t.Errorf("expected %s header to be %q, but got %q", headerIfMatch, tt.want, got)
+					}
+					return httpmock.NewStringResponse(200, `{"id":"test","updateTime":1682089800}`), nil
+				},
+			)
+
+			got, err := newTestClient(mock).UpdateValue(context.Background(), &model.Value{ID: "test"}, tt.revision)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.UpdateTime != "1682089800" {
+				t.Fatalf("expected update time 1682089800, but got %q", got.UpdateTime)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteValue_NotFound(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Delete",
+		httpmock.NewStringResponder(http.StatusNotFound, `{"code":"not_found"}`),
+	)
+
+	if err := newTestClient(mock).DeleteValue(context.Background(), "test"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_ListValues(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/List",
+		httpmock.NewStringResponder(200, `{"values":[{"id":"a"},{"id":"b"}],"nextPageToken":"next"}`),
+	)
+
+	got, err := newTestClient(mock).ListValues(context.Background(), &model.ListValuesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Values) != 2 || got.Values[0].ID != "a" || got.Values[1].ID != "b" {
+		t.Fatalf("unexpected values: %+v", got.Values)
+	}
+	if got.NextPageToken != "next" {
+		t.Fatalf("expected next, but got %s", got.NextPageToken)
+	}
+}
+
+func TestClient_ContextCanceled(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Get",
+		httpmock.NewStringResponder(200, `{"id":"test"}`),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newTestClient(mock).GetValue(ctx, "test")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, but got %v", err)
+	}
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Get",
+		httpmock.NewStringResponder(200, `{"id":"test"}`).Delay(time.Second),
+	)
+
+	// The attempts are timed by the HTTP client, or by the transport under a concurrency limit.
+	for _, limit := range []int{0, 1} {
+		c := New(Config{
+			Endpoint:       testEndpoint,
+			RequestTimeout: 10 * time.Millisecond,
+			HTTPClient: &http.Client{
+				Transport: mock,
+			},
+			MaxConcurrentRequests: limit,
+		}).(*client)
+		c.client.RetryMax = 0
+
+		start := time.Now()
+		if _, err := c.GetValue(context.Background(), "test"); err == nil {
+			t.Fatalf("expected timeout error with a limit of %d, but got nil", limit)
+		}
+		if elapsed := time.Since(start); elapsed >= time.Second {
+			t.Fatalf("expected request to time out early with a limit of %d, but took %s", limit, elapsed)
+		}
+	}
+}
+
+func TestListAllValues(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/List",
+		func(req *http.Request) (*http.Response, error) {
+			var in model.ListValuesRequest
+			if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+				return nil, err
+			}
+			switch in.PageToken {
+			case "":
+				return httpmock.NewStringResponse(200, `{"values":[{"id":"a"},{"id":"b"}],"nextPageToken":"page2"}`), nil
+			case "page2":
+				return httpmock.NewStringResponse(200, `{"values":[{"id":"c"}]}`), nil
+			default:
+				return httpmock.NewStringResponse(400, `{"code":"invalid_argument"}`), nil
+			}
+		},
+	)
+
+	got, err := ListAllValues(context.Background(), newTestClient(mock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0].ID != "a" || got[2].ID != "c" {
+		t.Fatalf("unexpected values: %+v", got)
+	}
+}
+
+func TestListAllValues_RepeatedToken(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/List",
+		httpmock.NewStringResponder(200, `{"values":[{"id":"a"}],"nextPageToken":"same"}`),
+	)
+
+	if _, err := ListAllValues(context.Background(), newTestClient(mock)); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+}
@@ -0,0 +1,135 @@
+package edgeclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Code is a Connect error code returned by the Edge API.
+type Code string
+
+const (
+	CodeUnknown            Code = "unknown"
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeNotFound           Code = "not_found"
+	CodeAlreadyExists      Code = "already_exists"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeAborted            Code = "aborted"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodeUnavailable        Code = "unavailable"
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrConflict         = errors.New("conflict")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrUnavailable      = errors.New("unavailable")
+)
+
+// Error is a failed Edge API call. It matches one of the Err* sentinels with errors.Is
+// depending on its Code.
+type Error struct {
+	StatusCode int
+	Code       Code
+	Message    string
+	// Actor is who made the change a conflict is about, when the Edge API reports it.
+	Actor string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("edge: %s (status %d)", e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("edge: %s (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case CodeNotFound:
+		return ErrNotFound
+	case CodeAlreadyExists:
+		return ErrAlreadyExists
+	case CodeFailedPrecondition, CodeAborted:
+		return ErrConflict
+	case CodeInvalidArgument:
+		return ErrInvalidArgument
+	case CodePermissionDenied, CodeUnauthenticated:
+		return ErrPermissionDenied
+	case CodeUnavailable:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// IsNotFound reports whether err means the requested value does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err means the value was changed since the revision an update was
+// conditioned on.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+type connectError struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Details []struct {
+		// Debug is the JSON form of the detail. The actor of a conflicting change is expected
+		// there, as it is the only place a Connect error carries structured data in JSON.
+		Debug struct {
+			Actor string `json:"actor"`
+		} `json:"debug"`
+	} `json:"details"`
+}
+
+func parseError(resp *http.Response) error {
+	b, _ := io.ReadAll(resp.Body)
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		Code:       codeFromStatus(resp.StatusCode),
+		Message:    string(b),
+	}
+	var ce connectError
+	if err := json.Unmarshal(b, &ce); err == nil && ce.Code != "" {
+		e.Code = ce.Code
+		e.Message = ce.Message
+		for _, d := range ce.Details {
+			if d.Debug.Actor != "" {
+				e.Actor = d.Debug.Actor
+				break
+			}
+		}
+	}
+	return e
+}
+
+// codeFromStatus maps an HTTP status to a Code for responses without a Connect error body.
+func codeFromStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodePermissionDenied
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusPreconditionFailed:
+		return CodeFailedPrecondition
+	case http.StatusConflict:
+		return CodeAlreadyExists
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	default:
+		return CodeUnknown
+	}
+}
@@ -0,0 +1,34 @@
+package edgeclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+)
+
+const listPageSize = 100
+
+// ListAllValues pages through ListValues and returns every value.
+func ListAllValues(ctx context.Context, c Client) ([]*model.Value, error) {
+	var (
+		values []*model.Value
+		seen   = make(map[string]bool)
+		req    = &model.ListValuesRequest{PageSize: listPageSize}
+	)
+	for {
+		res, err := c.ListValues(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, res.Values...)
+		if res.NextPageToken == "" {
+			return values, nil
+		}
+		if seen[res.NextPageToken] {
+			return nil, fmt.Errorf("edge: list returned page token %q twice", res.NextPageToken)
+		}
+		seen[res.NextPageToken] = true
+		req = &model.ListValuesRequest{PageSize: listPageSize, PageToken: res.NextPageToken}
+	}
+}
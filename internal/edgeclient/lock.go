@@ -0,0 +1,104 @@
+package edgeclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeyedMutex serializes callers sharing the same key while letting different keys proceed
+// concurrently.
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedMutexEntry
+}
+
+type keyedMutexEntry struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewKeyedMutex returns a KeyedMutex with no key locked.
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{locks: make(map[string]*keyedMutexEntry)}
+}
+
+// Lock locks key and returns the function that unlocks it.
+func (k *KeyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	e, ok := k.locks[key]
+	if !ok {
+		e = &keyedMutexEntry{}
+		k.locks[key] = e
+	}
+	e.refs++
+	k.mu.Unlock()
+
+	e.mu.Lock()
+	return func() {
+		e.mu.Unlock()
+		k.mu.Lock()
+		e.refs--
+		if e.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// limitTransport caps the number of HTTP requests in flight. A slot is held until the
+// response body is closed. The timeout of a request only starts once it holds a slot, so that
+// waiting for one does not count against it.
+type limitTransport struct {
+	base    http.RoundTripper
+	sem     chan struct{}
+	timeout time.Duration
+}
+
+func newLimitTransport(base http.RoundTripper, n int, timeout time.Duration) *limitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &limitTransport{base: base, sem: make(chan struct{}, n), timeout: timeout}
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	cancel := func() {}
+	if t.timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), t.timeout)
+		req = req.WithContext(ctx)
+	}
+	release := func() {
+		cancel()
+		<-t.sem
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
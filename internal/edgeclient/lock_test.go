@@ -0,0 +1,154 @@
+package edgeclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+)
+
+// concurrencyTransport records the maximum number of concurrent requests per value ID and overall.
+type concurrencyTransport struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	maxPerID map[string]int
+	total    atomic.Int32
+	maxTotal atomic.Int32
+}
+
+func newConcurrencyTransport() *concurrencyTransport {
+	return &concurrencyTransport{
+		inFlight: make(map[string]int),
+		maxPerID: make(map[string]int),
+	}
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b, _ := io.ReadAll(req.Body)
+	id := string(b)
+
+	t.mu.Lock()
+	t.inFlight[id]++
+	t.maxPerID[id] = max(t.maxPerID[id], t.inFlight[id])
+	t.mu.Unlock()
+	n := t.total.Add(1)
+	for {
+		m := t.maxTotal.Load()
+		if n <= m || t.maxTotal.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	t.total.Add(-1)
+	t.mu.Lock()
+	t.inFlight[id]--
+	t.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestClient_Concurrency(t *testing.T) {
+	t.Parallel()
+	tr := newConcurrencyTransport()
+	c := New(Config{
+		Endpoint: testEndpoint,
+		HTTPClient: &http.Client{
+			Transport: tr,
+		},
+		MaxConcurrentRequests: 3,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("value-%d", i%4)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.UpdateValue(context.Background(), &model.Value{ID: id}, ""); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for id, n := range tr.maxPerID {
+		if n != 1 {
+			t.Errorf("expected writes to %s to be serialized, but saw %d concurrent requests", id, n)
+		}
+	}
+	if got := tr.maxTotal.Load(); got > 3 {
+		t.Errorf("expected at most 3 concurrent requests, but saw %d", got)
+	}
+	if got := tr.maxTotal.Load(); got < 2 {
+		t.Errorf("expected different values to be written concurrently, but saw %d", got)
+	}
+}
+
+func TestClient_ConcurrencyTimeout(t *testing.T) {
+	t.Parallel()
+	c := New(Config{
+		Endpoint:       testEndpoint,
+		RequestTimeout: 50 * time.Millisecond,
+		HTTPClient: &http.Client{
+			Transport: newConcurrencyTransport(),
+		},
+		MaxConcurrentRequests: 1,
+	}).(*client)
+	c.client.RetryMax = 0
+
+	// Queued behind each other, the requests take longer than the timeout of a single one.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("value-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.CreateValue(context.Background(), &model.Value{ID: id}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestKeyedMutex(t *testing.T) {
+	t.Parallel()
+	k := NewKeyedMutex()
+
+	unlockA := k.Lock("a")
+	unlockB := k.Lock("b")
+	unlockB()
+
+	locked := make(chan struct{})
+	go func() {
+		unlock := k.Lock("a")
+		close(locked)
+		unlock()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("expected second lock of a to block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlockA()
+	<-locked
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.locks) != 0 {
+		t.Fatalf("expected released keys to be removed, but got %d", len(k.locks))
+	}
+}
@@ -0,0 +1,133 @@
+package edgeclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RetryPolicy controls how failed Edge API requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	MinWait     time.Duration
+	MaxWait     time.Duration
+	// StatusCodes lists the HTTP statuses that are retried.
+	StatusCodes []int
+	// RespectRetryAfter waits for the Retry-After header of 429 and 503 responses,
+	// capped at MaxWait, instead of the exponential backoff.
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 6,
+		MinWait:     time.Second,
+		MaxWait:     30 * time.Second,
+		StatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RespectRetryAfter: true,
+	}
+}
+
+type nonIdempotentKey struct{}
+
+// withNonIdempotent marks requests made with ctx as unsafe to replay once the server may have
+// processed them.
+func withNonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonIdempotentKey{}, true)
+}
+
+func isNonIdempotent(ctx context.Context) bool {
+	v, _ := ctx.Value(nonIdempotentKey{}).(bool)
+	return v
+}
+
+type attemptsKey struct{}
+
+// withAttempts makes requests made with ctx record the number of attempts made in n.
+func withAttempts(ctx context.Context, n *int) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, n)
+}
+
+// countAttempt is the request hook recording the attempts of requests made through withAttempts.
+func countAttempt(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	if n, ok := req.Context().Value(attemptsKey{}).(*int); ok {
+		*n = attempt + 1
+	}
+}
+
+func (p RetryPolicy) checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if err != nil {
+		// A request that failed before reaching the server is always safe to replay.
+		// Anything else, such as a timeout, may have been applied already.
+		if isNonIdempotent(ctx) && !isDialError(err) {
+			return false, err
+		}
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	if !slices.Contains(p.StatusCodes, resp.StatusCode) {
+		return false, nil
+	}
+	if isNonIdempotent(ctx) {
+		// Only these statuses guarantee the request was rejected without being processed.
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable, nil
+	}
+	return true, nil
+}
+
+func (p RetryPolicy) backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if p.RespectRetryAfter && resp != nil &&
+		(resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > max {
+				return max
+			}
+			return wait
+		}
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,258 @@
+package edgeclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/jarcoal/httpmock"
+)
+
+func newRetryTestClient(mock *httpmock.MockTransport, policy RetryPolicy) Client {
+	return New(Config{
+		Endpoint: testEndpoint,
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+		Retry: &policy,
+	})
+}
+
+func testRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MaxAttempts = 3
+	p.MinWait = time.Millisecond
+	p.MaxWait = time.Millisecond
+	return p
+}
+
+func TestRetryPolicy_Statuses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		calls  int
+	}{
+		{
+			name:   "get retries 500",
+			path:   "/service.Value/Get",
+			status: http.StatusInternalServerError,
+			calls:  3,
+		},
+		{
+			name:   "get does not retry 400",
+			path:   "/service.Value/Get",
+			status: http.StatusBadRequest,
+			calls:  1,
+		},
+		{
+			name:   "create retries 503",
+			path:   "/service.Value/Create",
+			status: http.StatusServiceUnavailable,
+			calls:  3,
+		},
+		{
+			name:   "create does not retry 504",
+			path:   "/service.Value/Create",
+			status: http.StatusGatewayTimeout,
+			calls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mock := httpmock.NewMockTransport()
+			mock.RegisterResponder(http.MethodPost, testEndpoint+tt.path, httpmock.NewStringResponder(tt.status, ""))
+			c := newRetryTestClient(mock, testRetryPolicy())
+
+			var err error
+			if tt.path == "/service.Value/Create" {
+				_, err = c.CreateValue(context.Background(), &model.Value{ID: "test"})
+			} else {
+				_, err = c.GetValue(context.Background(), "test")
+			}
+			if err == nil {
+				t.Fatal("expected error, but got nil")
+			}
+			if got := mock.GetTotalCallCount(); got != tt.calls {
+				t.Fatalf("expected %d calls, but got %d", tt.calls, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_CreateTimeout(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Create",
+		httpmock.NewErrorResponder(&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Update",
+		httpmock.NewErrorResponder(&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}),
+	)
+	c := newRetryTestClient(mock, testRetryPolicy())
+
+	if _, err := c.CreateValue(context.Background(), &model.Value{ID: "test"}); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+	if got := mock.GetCallCountInfo()["POST "+testEndpoint+"/service.Value/Create"]; got != 1 {
+		t.Fatalf("expected create to be attempted once, but got %d", got)
+	}
+
+	if _, err := c.UpdateValue(context.Background(), &model.Value{ID: "test"}, ""); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+	if got := mock.GetCallCountInfo()["POST "+testEndpoint+"/service.Value/Update"]; got != 3 {
+		t.Fatalf("expected update to be attempted 3 times, but got %d", got)
+	}
+}
+
+func TestRetryPolicy_UpdateAppliedBeforeRetry(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		// changed is applied to the stored Value after the first attempt.
+		changed  func(*model.Value)
+		conflict bool
+	}{
+		"applied":         {},
+		"changed between": {changed: func(v *model.Value) { v.Description = "changed" }, conflict: true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			stored := model.Value{ID: "test", Description: "old", UpdateTime: "1682089734"}
+			mock := httpmock.NewMockTransport()
+			mock.RegisterResponder(
+				http.MethodPost,
+				testEndpoint+"/service.Value/Update",
+				func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					if req.Header.Get("If-Match") != `"`+string(stored.UpdateTime)+`"` {
+						return httpmock.NewStringResponse(412, `{"code":"failed_precondition"}`), nil
+					}
+					// The update is applied, but its response is lost to a gateway timeout.
+					if err := json.NewDecoder(req.Body).Decode(&stored); err != nil {
+						return nil, err
+					}
+					stored.UpdateTime = "1682089800"
+					if tt.changed != nil {
+						tt.changed(&stored)
+					}
+					return httpmock.NewStringResponse(http.StatusGatewayTimeout, ""), nil
+				},
+			)
+			mock.RegisterResponder(
+				http.MethodPost,
+				testEndpoint+"/service.Value/Get",
+				func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					return httpmock.NewJsonResponse(200, &stored)
+				},
+			)
+			c := newRetryTestClient(mock, testRetryPolicy())
+
+			v, err := c.UpdateValue(context.Background(), &model.Value{ID: "test", Description: "new"}, "1682089734")
+			if tt.conflict {
+				if !IsConflict(err) {
+					t.Fatalf("expected a conflict, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.Description != "new" || v.UpdateTime != "1682089800" {
+				t.Fatalf("expected the applied value, but got %+v", v)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_CreateDialError(t *testing.T) {
+	t.Parallel()
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		testEndpoint+"/service.Value/Create",
+		httpmock.NewErrorResponder(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}),
+	)
+	c := newRetryTestClient(mock, testRetryPolicy())
+
+	if _, err := c.CreateValue(context.Background(), &model.Value{ID: "test"}); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+	if got := mock.GetTotalCallCount(); got != 3 {
+		t.Fatalf("expected 3 calls, but got %d", got)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		status int
+		header string
+		want   time.Duration
+	}{
+		{
+			name:   "retry after seconds",
+			policy: RetryPolicy{RespectRetryAfter: true},
+			status: http.StatusTooManyRequests,
+			header: "3",
+			want:   3 * time.Second,
+		},
+		{
+			name:   "retry after capped",
+			policy: RetryPolicy{RespectRetryAfter: true},
+			status: http.StatusServiceUnavailable,
+			header: "120",
+			want:   10 * time.Second,
+		},
+		{
+			name:   "retry after ignored",
+			policy: RetryPolicy{RespectRetryAfter: false},
+			status: http.StatusTooManyRequests,
+			header: "3",
+			want:   time.Second,
+		},
+		{
+			name:   "retry after only for 429 and 503",
+			policy: RetryPolicy{RespectRetryAfter: true},
+			status: http.StatusInternalServerError,
+			header: "3",
+			want:   time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			resp.Header.Set("Retry-After", tt.header)
+			got := tt.policy.backoff(time.Second, 10*time.Second, 0, resp)
+			if got != tt.want {
+				t.Fatalf("expected %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package evaluation resolves which variant of a value applies to a set of variables, mirroring
+// how the Edge API evaluates targeting rules.
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/expr"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+)
+
+// ErrUnsupported is returned for values whose targeting rules cannot be evaluated locally.
+var ErrUnsupported = errors.New("only CEL targeting rules can be evaluated locally")
+
+// Result is the outcome of evaluating a value.
+type Result struct {
+	Variant string
+	// Rule is the index of the targeting rule that matched, or -1 when the default variant applied.
+	Rule int
+	// Errors holds the evaluation error of each rule that failed and was therefore skipped.
+	Errors map[int]error
+}
+
+// Evaluate runs the targeting rules of v in order against vars. The first matching rule decides
+// the variant, falling back to the default variant. A rule that fails to evaluate, for example
+// because it references a missing variable, does not match: the Edge API is assumed to skip such
+// rules the same way. The errors are kept in the Result so that a failing test can report them.
+func Evaluate(v *model.Value, vars map[string]any) (Result, error) {
+	res := Result{Variant: v.DefaultVariant, Rule: -1}
+	for i, rule := range v.Targeting.Rules {
+		if rule.Spec != model.ValueTargetingRuleSpecCEL {
+			return Result{}, ErrUnsupported
+		}
+		matched, err := expr.EvalTargeting(rule.Expr, vars)
+		if err != nil {
+			if res.Errors == nil {
+				res.Errors = make(map[int]error)
+			}
+			res.Errors[i] = err
+			continue
+		}
+		if matched {
+			res.Variant = rule.Variant
+			res.Rule = i
+			return res, nil
+		}
+	}
+	return res, nil
+}
+
+// Failure describes a test whose expected variant differs from the evaluated one. The provider
+// reports it in terms of the attributes of the value resource.
+type Failure struct {
+	Index  int
+	Test   *model.EvaluationTest
+	Result Result
+}
+
+// RunTests evaluates every test of v and returns those whose expectation is not met.
+func RunTests(v *model.Value) ([]*Failure, error) {
+	var failures []*Failure
+	for i, t := range v.Tests {
+		res, err := Evaluate(v, t.Variables)
+		if err != nil {
+			return nil, err
+		}
+		if res.Variant != t.Expected {
+			failures = append(failures, &Failure{Index: i, Test: t, Result: res})
+		}
+	}
+	return failures, nil
+}
+
+// DecodeVariables decodes test variables, keeping integers as int64 so that they compare with
+// CEL integer literals the same way the Edge API does.
+func DecodeVariables(s string) (map[string]any, error) {
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	var vars map[string]any
+	if err := d.Decode(&vars); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(vars).(map[string]any), nil
+}
+
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,130 @@
+package evaluation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+)
+
+func testValue() *model.Value {
+	return &model.Value{
+		DefaultVariant: "off",
+		Targeting: model.ValueTargeting{
+			Rules: []model.ValueTargetingRule{
+				{Variant: "on", Spec: model.ValueTargetingRuleSpecCEL, Expr: "env == 'dev'"},
+				{Variant: "beta", Spec: model.ValueTargetingRuleSpecCEL, Expr: "userId == 'XXX'"},
+			},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		vars       string
+		want       string
+		wantRule   int
+		wantErrors int
+	}{
+		{
+			name:     "first rule",
+			vars:     `{"env":"dev","userId":"XXX"}`,
+			want:     "on",
+			wantRule: 0,
+		},
+		{
+			name:     "second rule",
+			vars:     `{"env":"prd","userId":"XXX"}`,
+			want:     "beta",
+			wantRule: 1,
+		},
+		{
+			name:       "default with missing variable",
+			vars:       `{"env":"prd"}`,
+			want:       "off",
+			wantRule:   -1,
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			vars, err := DecodeVariables(tt.vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := Evaluate(testValue(), vars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Variant != tt.want || got.Rule != tt.wantRule {
+				t.Fatalf("expected %s from rule %d, but got %s from rule %d", tt.want, tt.wantRule, got.Variant, got.Rule)
+			}
+			if len(got.Errors) != tt.wantErrors {
+				t.Fatalf("expected %d errors, but got %v", tt.wantErrors, got.Errors)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Unsupported(t *testing.T) {
+	t.Parallel()
+	v := testValue()
+	v.Targeting.Rules[1].Spec = model.ValueTargetingRuleSpecJsonLogic
+
+	_, err := Evaluate(v, map[string]any{"env": "prd"})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, but got %v", err)
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	t.Parallel()
+	v := testValue()
+	v.Tests = []*model.EvaluationTest{
+		{Variables: map[string]any{"env": "dev"}, Expected: "on"},
+		{Variables: map[string]any{"env": "prd"}, Expected: "on"},
+		{Variables: map[string]any{"env": "prd", "userId": "XXX"}, Expected: "on"},
+		// The second rule fails on the missing userId and is skipped rather than failing the test.
+		{Variables: map[string]any{"env": "prd"}, Expected: "off"},
+	}
+
+	failures, err := RunTests(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures, but got %d", len(failures))
+	}
+	if failures[0].Index != 1 || failures[0].Result.Rule != -1 || failures[0].Result.Variant != "off" {
+		t.Fatalf("unexpected failure: %+v", failures[0])
+	}
+	if _, ok := failures[0].Result.Errors[1]; !ok {
+		t.Fatalf("expected the error of the skipped rule to be reported, but got %+v", failures[0].Result)
+	}
+	if failures[1].Index != 2 || failures[1].Result.Rule != 1 || failures[1].Result.Variant != "beta" {
+		t.Fatalf("unexpected failure: %+v", failures[1])
+	}
+}
+
+func TestDecodeVariables(t *testing.T) {
+	t.Parallel()
+	got, err := DecodeVariables(`{"count":1,"ratio":0.5,"nested":{"ids":[1,2]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got["count"].(int64); !ok {
+		t.Fatalf("expected count to be int64, but got %T", got["count"])
+	}
+	if _, ok := got["ratio"].(float64); !ok {
+		t.Fatalf("expected ratio to be float64, but got %T", got["ratio"])
+	}
+	ids := got["nested"].(map[string]any)["ids"].([]any)
+	if _, ok := ids[0].(int64); !ok {
+		t.Fatalf("expected nested ids to be int64, but got %T", ids[0])
+	}
+}
@@ -0,0 +1,166 @@
+// Package expr compiles the CEL expressions used by Edge targeting rules and JSON transforms.
+package expr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// CheckTargeting parses and type-checks a targeting rule, which must evaluate to a bool.
+func CheckTargeting(src string) error {
+	_, _, err := compile(src, cel.BoolType)
+	return err
+}
+
+// CheckTransform parses and type-checks a transform of the JSON document doc, which must
+// evaluate to the same kind of document: a map for an object, a list for an array. Transforms of
+// scalars, or of a document that is not known yet, may evaluate to anything.
+func CheckTransform(src string, doc any) error {
+	_, _, err := compile(src, documentType(doc))
+	return err
+}
+
+// documentType returns the CEL type of the JSON document doc.
+func documentType(doc any) *cel.Type {
+	switch doc.(type) {
+	case map[string]any:
+		return cel.MapType(cel.StringType, cel.DynType)
+	case []any:
+		return cel.ListType(cel.DynType)
+	default:
+		return cel.DynType
+	}
+}
+
+// EvalTargeting evaluates a targeting rule against vars and reports whether it matched.
+func EvalTargeting(src string, vars map[string]any) (bool, error) {
+	checked, env, err := compile(src, cel.BoolType)
+	if err != nil {
+		return false, err
+	}
+	prg, err := env.Program(checked)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %s, not bool", out.Type())
+	}
+	return matched, nil
+}
+
+// compile parses src, declares each of its free variables as dyn since their types are only
+// known by the caller of the Edge API, and type-checks the result against want.
+func compile(src string, want *cel.Type) (*cel.Ast, *cel.Env, error) {
+	base, err := cel.NewEnv(append(extensions(), cel.CrossTypeNumericComparisons(true))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, iss := base.Parse(src)
+	if iss.Err() != nil {
+		return nil, nil, iss.Err()
+	}
+
+	opts := make([]cel.EnvOption, 0)
+	for _, name := range freeVariables(parsed) {
+		opts = append(opts, cel.Variable(name, cel.DynType))
+	}
+	env, err := base.Extend(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	checked, iss := env.Check(parsed)
+	if iss.Err() != nil {
+		return nil, nil, iss.Err()
+	}
+
+	out := checked.OutputType()
+	if !out.IsExactType(cel.DynType) && !want.IsAssignableType(out) {
+		return nil, nil, fmt.Errorf("expression must evaluate to %s, but evaluates to %s", want, out)
+	}
+	return checked, env, nil
+}
+
+// freeVariables returns the identifiers of a that are not bound by a comprehension.
+func freeVariables(a *cel.Ast) []string {
+	idents := make(map[string]bool)
+	bound := make(map[string]bool)
+	ast.PostOrderVisit(a.NativeRep().Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		switch e.Kind() {
+		case ast.IdentKind:
+			idents[e.AsIdent()] = true
+		case ast.ComprehensionKind:
+			c := e.AsComprehension()
+			bound[c.IterVar()] = true
+			bound[c.AccuVar()] = true
+		}
+	}))
+
+	names := make([]string, 0, len(idents))
+	for name := range idents {
+		if !bound[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// extensions declares the functions the Edge API adds to the standard CEL environment.
+func extensions() []cel.EnvOption {
+	mapType := cel.MapType(cel.StringType, cel.DynType)
+	keysType := cel.ListType(cel.StringType)
+	return []cel.EnvOption{
+		cel.Function("deleteKey",
+			cel.MemberOverload("map_deleteKey_list", []*cel.Type{mapType, keysType}, mapType,
+				cel.BinaryBinding(func(m, keys ref.Val) ref.Val {
+					return filterKeys(m, keys, false)
+				}),
+			),
+		),
+		cel.Function("selectKey",
+			cel.MemberOverload("map_selectKey_list", []*cel.Type{mapType, keysType}, mapType,
+				cel.BinaryBinding(func(m, keys ref.Val) ref.Val {
+					return filterKeys(m, keys, true)
+				}),
+			),
+		),
+	}
+}
+
+// filterKeys returns a copy of m holding only the entries whose key is (keep) or is not (!keep)
+// listed in keys.
+func filterKeys(m, keys ref.Val, keep bool) ref.Val {
+	mapper, ok := m.(traits.Mapper)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(m)
+	}
+	lister, ok := keys.(traits.Lister)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(keys)
+	}
+
+	listed := make(map[ref.Val]bool)
+	for it := lister.Iterator(); it.HasNext() == types.True; {
+		listed[it.Next()] = true
+	}
+
+	out := make(map[ref.Val]ref.Val)
+	for it := mapper.Iterator(); it.HasNext() == types.True; {
+		k := it.Next()
+		if listed[k] == keep {
+			out[k] = mapper.Get(k)
+		}
+	}
+	return types.DefaultTypeAdapter.NativeToValue(out)
+}
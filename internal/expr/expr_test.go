@@ -0,0 +1,175 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckTargeting(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{
+			src: "env == 'dev'",
+		},
+		{
+			src: "userId == 'XXX' && count > 1",
+		},
+		{
+			src: "tenant in ['a', 'b'] || user.tags.exists(t, t == 'beta')",
+		},
+		{
+			src:     "env == ",
+			wantErr: "Syntax error",
+		},
+		{
+			src:     "env = 'dev'",
+			wantErr: "<input>:1:5",
+		},
+		{
+			src:     "'dev'",
+			wantErr: "must evaluate to bool",
+		},
+		{
+			src:     "size(1)",
+			wantErr: "found no matching overload",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.src, func(t *testing.T) {
+			t.Parallel()
+			err := CheckTargeting(tt.src)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckTransform(t *testing.T) {
+	t.Parallel()
+	object := map[string]any{"items": []any{}}
+	tests := []struct {
+		src     string
+		doc     any
+		wantErr string
+	}{
+		{
+			src: `{"items":items.map(item, item.viewable ? item : item.deleteKey(["content"]))}`,
+			doc: object,
+		},
+		{
+			src: `{"items":items.map(item, item.viewable ? item.selectKey(["content"]) : item)}`,
+			doc: object,
+		},
+		{
+			src:     `{"items":items.map(item, item.dropKey(["content"]))}`,
+			doc:     object,
+			wantErr: "undeclared reference to 'dropKey'",
+		},
+		{
+			src:     `items.size()`,
+			doc:     object,
+			wantErr: "must evaluate to map",
+		},
+		{
+			src: `[1, 2].map(x, x * 2)`,
+			doc: []any{},
+		},
+		{
+			src:     `{"items": []}`,
+			doc:     []any{},
+			wantErr: "must evaluate to list",
+		},
+		{
+			src: `"banner"`,
+			doc: "banner",
+		},
+		{
+			src: `{"items": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.src, func(t *testing.T) {
+			t.Parallel()
+			err := CheckTransform(tt.src, tt.doc)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEvalTargeting(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src     string
+		vars    map[string]any
+		want    bool
+		wantErr string
+	}{
+		{
+			src:  "env == 'dev'",
+			vars: map[string]any{"env": "dev"},
+			want: true,
+		},
+		{
+			src:  "env == 'dev'",
+			vars: map[string]any{"env": "prd"},
+			want: false,
+		},
+		{
+			src:  "count > 1",
+			vars: map[string]any{"count": 1.5},
+			want: true,
+		},
+		{
+			src:  "user.tags.exists(t, t == 'beta')",
+			vars: map[string]any{"user": map[string]any{"tags": []any{"alpha", "beta"}}},
+			want: true,
+		},
+		{
+			src:     "userId == 'XXX'",
+			vars:    map[string]any{"env": "dev"},
+			wantErr: "no such attribute",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.src, func(t *testing.T) {
+			t.Parallel()
+			got, err := EvalTargeting(tt.src, tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, but got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package jsontypes provides a string attribute type holding JSON, compared by meaning rather
+// than by text.
+package jsontypes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable = NormalizedType{}
+	_ xattr.TypeWithValidate  = NormalizedType{}
+)
+
+// NormalizedType is the attribute type of Normalized values.
+type NormalizedType struct {
+	basetypes.StringType
+}
+
+func (t NormalizedType) String() string {
+	return "jsontypes.NormalizedType"
+}
+
+func (t NormalizedType) ValueType(_ context.Context) attr.Value {
+	return Normalized{}
+}
+
+func (t NormalizedType) Equal(o attr.Type) bool {
+	other, ok := o.(NormalizedType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t NormalizedType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return Normalized{StringValue: in}, nil
+}
+
+func (t NormalizedType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	return Normalized{StringValue: stringValue}, nil
+}
+
+// Validate reports an error when a known value is not valid JSON.
+func (t NormalizedType) Validate(_ context.Context, in tftypes.Value, p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if in.Type() == nil || !in.IsKnown() || in.IsNull() {
+		return diags
+	}
+	var s string
+	if err := in.As(&s); err != nil {
+		diags.AddAttributeError(p, "Invalid Terraform Value", "Unable to convert the value to a string: "+err.Error())
+		return diags
+	}
+	if !json.Valid([]byte(s)) {
+		diags.AddAttributeError(p, "Invalid JSON String Value", "The value is not valid JSON:\n\n"+s)
+	}
+	return diags
+}
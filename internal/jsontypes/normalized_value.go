@@ -0,0 +1,134 @@
+package jsontypes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var _ basetypes.StringValuableWithSemanticEquals = Normalized{}
+
+// Normalized is a JSON string. Two values are semantically equal when they decode to the same
+// document, so key order and whitespace never show up as a diff.
+type Normalized struct {
+	basetypes.StringValue
+}
+
+func NewNormalizedNull() Normalized {
+	return Normalized{StringValue: basetypes.NewStringNull()}
+}
+
+func NewNormalizedUnknown() Normalized {
+	return Normalized{StringValue: basetypes.NewStringUnknown()}
+}
+
+func NewNormalizedValue(v string) Normalized {
+	return Normalized{StringValue: basetypes.NewStringValue(v)}
+}
+
+func (v Normalized) Type(_ context.Context) attr.Type {
+	return NormalizedType{}
+}
+
+func (v Normalized) Equal(o attr.Value) bool {
+	other, ok := o.(Normalized)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v Normalized) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(Normalized)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			"An unexpected value type was received while performing semantic equality checks. "+
+				"Please report this to the provider developers.",
+		)
+		return false, diags
+	}
+	return Equivalent(v.ValueString(), newValue.ValueString()), diags
+}
+
+// Equivalent reports whether a and b are valid JSON encoding the same document. Numbers are
+// compared by their exact value, so 1, 1.0 and 1e0 are the same number and no precision is lost.
+func Equivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+	va, err := Decode(a)
+	if err != nil {
+		return false
+	}
+	vb, err := Decode(b)
+	if err != nil {
+		return false
+	}
+	return equal(va, vb)
+}
+
+// equal reports whether the documents a and b decoded by Decode are the same.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ra, ok := new(big.Rat).SetString(a.String())
+		if !ok {
+			return false
+		}
+		rb, ok := new(big.Rat).SetString(b.String())
+		return ok && ra.Cmp(rb) == 0
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, va := range a {
+			vb, ok := b[k]
+			if !ok || !equal(va, vb) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// Decode decodes the JSON document s, keeping numbers as json.Number so that no precision is
+// lost. Anything after the document is an error.
+func Decode(s string) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
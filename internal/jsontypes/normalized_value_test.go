@@ -0,0 +1,69 @@
+package jsontypes
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalized_StringSemanticEquals(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{
+			a:    `{"a":1,"b":[true,null]}`,
+			b:    "{\n  \"b\": [true, null],\n  \"a\": 1\n}",
+			want: true,
+		},
+		{
+			a:    `[1,2]`,
+			b:    `[2,1]`,
+			want: false,
+		},
+		{
+			a:    `{"n":9007199254740993}`,
+			b:    `{"n":9007199254740992}`,
+			want: false,
+		},
+		{
+			a:    `{"a":1}`,
+			b:    `{"a":`,
+			want: false,
+		},
+		{
+			a:    `{"a":[1,100,0.5]}`,
+			b:    `{"a":[1.0,1e2,5E-1]}`,
+			want: true,
+		},
+		{
+			a:    `{"a":1}`,
+			b:    `{"a":"1"}`,
+			want: false,
+		},
+		{
+			a:    `{"a":1}`,
+			b:    `{"a":1} {"b":2}`,
+			want: false,
+		},
+		{
+			a:    `{"a":{"b":null}}`,
+			b:    `{"a":{}}`,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			got, diags := NewNormalizedValue(tt.a).StringSemanticEquals(context.Background(), NewNormalizedValue(tt.b))
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package model
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
 
 type Value struct {
 	ID             string            `json:"id"`
@@ -12,6 +17,113 @@ type Value struct {
 	CreateTime     json.Number       `json:"createTime,omitempty"`
 	UpdateTime     json.Number       `json:"updateTime,omitempty"`
 	Tests          []*EvaluationTest `json:"tests,omitempty"`
+
+	// UnknownFields holds the fields of the Edge API representation that Value does not model,
+	// such as ones set from the Edge UI. They are written back unchanged. The nested types keep
+	// theirs the same way.
+	UnknownFields map[string]json.RawMessage `json:"-"`
+}
+
+// valueFields lists the JSON names of the fields Value models.
+var valueFields = jsonFieldNames(reflect.TypeOf(Value{}))
+
+// UnmarshalJSON decodes v, keeping the fields it does not model in UnknownFields. Numbers are
+// decoded as json.Number so that JSON variants keep their precision.
+func (v *Value) UnmarshalJSON(b []byte) error {
+	type value Value
+	var err error
+	v.UnknownFields, err = unmarshalFields(b, (*value)(v), valueFields)
+	return err
+}
+
+// MarshalJSON encodes v along with its UnknownFields. Modeled fields take precedence.
+func (v Value) MarshalJSON() ([]byte, error) {
+	type value Value
+	return marshalFields(value(v), v.UnknownFields, valueFields)
+}
+
+// KeepUnknownFields copies the unknown fields of current, the Value as stored by the Edge API,
+// into v, the Value about to replace it, wherever v has none of its own. Variants are matched by
+// name, named targeting rules by name, and other rules, tests and transforms by their position.
+func (v *Value) KeepUnknownFields(current *Value) {
+	if v.UnknownFields == nil {
+		v.UnknownFields = current.UnknownFields
+	}
+	for k, e := range v.Variants {
+		if c, ok := current.Variants[k]; ok {
+			e.keepUnknownFields(c)
+			v.Variants[k] = e
+		}
+	}
+	v.Targeting.keepUnknownFields(current.Targeting)
+	for i, t := range v.Tests {
+		if i < len(current.Tests) && t != nil && current.Tests[i] != nil && t.UnknownFields == nil {
+			t.UnknownFields = current.Tests[i].UnknownFields
+		}
+	}
+}
+
+// unmarshalFields decodes b into v, a pointer to a struct whose encoded fields are known, and
+// returns the other fields of b. Numbers are decoded as json.Number.
+func unmarshalFields(b []byte, v any, known map[string]bool) (map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	var unknown map[string]json.RawMessage
+	for k, f := range fields {
+		if known[k] {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+		unknown[k] = f
+	}
+	return unknown, nil
+}
+
+// marshalFields encodes v, a struct whose encoded fields are known, along with the unknown
+// fields. Known fields take precedence.
+func marshalFields(v any, unknown map[string]json.RawMessage, known map[string]bool) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for k, f := range unknown {
+		if _, ok := fields[k]; !ok && !known[k] {
+			fields[k] = f
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// jsonFieldNames returns the JSON names of the encoded fields of the struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		names[name] = true
+	}
+	return names
 }
 
 type (
@@ -22,6 +134,10 @@ type (
 		StringValue  *ValueStringValue  `json:"stringValue"`
 		JSONValue    *ValueJSONValue    `json:"jsonValue"`
 		IntegerValue *ValueIntegerValue `json:"integerValue"`
+		NumberValue  *ValueNumberValue  `json:"numberValue"`
+
+		// UnknownFields holds the fields of the variant that ValueEvaluation does not model.
+		UnknownFields map[string]json.RawMessage `json:"-"`
 	}
 
 	ValueBooleanValue struct {
@@ -33,28 +149,211 @@ type (
 	}
 
 	ValueJSONValue struct {
-		Value      map[string]any    `json:"value,omitempty"`
+		Value      any               `json:"value,omitempty"`
 		Transforms []*ValueTransform `json:"transforms,omitempty"`
+
+		// UnknownFields holds the fields of the JSON value that ValueJSONValue does not model.
+		UnknownFields map[string]json.RawMessage `json:"-"`
 	}
 
 	ValueIntegerValue struct {
 		Value json.Number `json:"value,omitempty"`
 	}
+
+	ValueNumberValue struct {
+		Value json.Number `json:"value,omitempty"`
+	}
+)
+
+var (
+	evaluationFields = jsonFieldNames(reflect.TypeOf(ValueEvaluation{}))
+	jsonValueFields  = jsonFieldNames(reflect.TypeOf(ValueJSONValue{}))
 )
 
+func (e *ValueEvaluation) UnmarshalJSON(b []byte) error {
+	type evaluation ValueEvaluation
+	var err error
+	e.UnknownFields, err = unmarshalFields(b, (*evaluation)(e), evaluationFields)
+	return err
+}
+
+func (e ValueEvaluation) MarshalJSON() ([]byte, error) {
+	type evaluation ValueEvaluation
+	return marshalFields(evaluation(e), e.UnknownFields, evaluationFields)
+}
+
+func (e *ValueEvaluation) keepUnknownFields(current ValueEvaluation) {
+	if e.UnknownFields == nil {
+		e.UnknownFields = current.UnknownFields
+	}
+	if e.JSONValue != nil && current.JSONValue != nil {
+		j := *e.JSONValue
+		j.keepUnknownFields(current.JSONValue)
+		e.JSONValue = &j
+	}
+}
+
+func (j *ValueJSONValue) UnmarshalJSON(b []byte) error {
+	type jsonValue ValueJSONValue
+	var err error
+	j.UnknownFields, err = unmarshalFields(b, (*jsonValue)(j), jsonValueFields)
+	return err
+}
+
+func (j ValueJSONValue) MarshalJSON() ([]byte, error) {
+	type jsonValue ValueJSONValue
+	return marshalFields(jsonValue(j), j.UnknownFields, jsonValueFields)
+}
+
+func (j *ValueJSONValue) keepUnknownFields(current *ValueJSONValue) {
+	if j.UnknownFields == nil {
+		j.UnknownFields = current.UnknownFields
+	}
+	for i, t := range j.Transforms {
+		if i < len(current.Transforms) && t != nil && current.Transforms[i] != nil && t.UnknownFields == nil {
+			t.UnknownFields = current.Transforms[i].UnknownFields
+		}
+	}
+}
+
+const (
+	ValueTypeBoolean = "boolean"
+	ValueTypeString  = "string"
+	ValueTypeJSON    = "json"
+	ValueTypeInteger = "integer"
+	ValueTypeNumber  = "number"
+)
+
+// Type returns the variant type of e, or an empty string when no value is set.
+func (e ValueEvaluation) Type() string {
+	switch {
+	case e.BooleanValue != nil:
+		return ValueTypeBoolean
+	case e.StringValue != nil:
+		return ValueTypeString
+	case e.JSONValue != nil:
+		return ValueTypeJSON
+	case e.IntegerValue != nil:
+		return ValueTypeInteger
+	case e.NumberValue != nil:
+		return ValueTypeNumber
+	default:
+		return ""
+	}
+}
+
+// Type returns the variant type shared by every variant of v. It returns an empty string when v
+// has no variants or mixes several types.
+func (v *Value) Type() string {
+	var typ string
+	for _, e := range v.Variants {
+		switch t := e.Type(); {
+		case typ == "":
+			typ = t
+		case t != typ:
+			return ""
+		}
+	}
+	return typ
+}
+
 type EvaluationTest struct {
 	Variables map[string]any `json:"variables"`
 	Expected  string         `json:"expected"`
+
+	// UnknownFields holds the fields of the test that EvaluationTest does not model.
+	UnknownFields map[string]json.RawMessage `json:"-"`
+}
+
+var evaluationTestFields = jsonFieldNames(reflect.TypeOf(EvaluationTest{}))
+
+func (t *EvaluationTest) UnmarshalJSON(b []byte) error {
+	type evaluationTest EvaluationTest
+	var err error
+	t.UnknownFields, err = unmarshalFields(b, (*evaluationTest)(t), evaluationTestFields)
+	return err
+}
+
+func (t EvaluationTest) MarshalJSON() ([]byte, error) {
+	type evaluationTest EvaluationTest
+	return marshalFields(evaluationTest(t), t.UnknownFields, evaluationTestFields)
 }
 
 type ValueTargeting struct {
 	Rules []ValueTargetingRule `json:"rules"`
+
+	// UnknownFields holds the fields of the targeting that ValueTargeting does not model.
+	UnknownFields map[string]json.RawMessage `json:"-"`
+}
+
+var targetingFields = jsonFieldNames(reflect.TypeOf(ValueTargeting{}))
+
+func (t *ValueTargeting) UnmarshalJSON(b []byte) error {
+	type targeting ValueTargeting
+	var err error
+	t.UnknownFields, err = unmarshalFields(b, (*targeting)(t), targetingFields)
+	return err
+}
+
+func (t ValueTargeting) MarshalJSON() ([]byte, error) {
+	type targeting ValueTargeting
+	return marshalFields(targeting(t), t.UnknownFields, targetingFields)
+}
+
+func (t *ValueTargeting) keepUnknownFields(current ValueTargeting) {
+	if t.UnknownFields == nil {
+		t.UnknownFields = current.UnknownFields
+	}
+	var unnamed []ValueTargetingRule
+	for _, r := range current.Rules {
+		if r.Name == "" {
+			unnamed = append(unnamed, r)
+		}
+	}
+	i := 0
+	for k, r := range t.Rules {
+		var c *ValueTargetingRule
+		if r.Name != "" {
+			for j := range current.Rules {
+				if current.Rules[j].Name == r.Name {
+					c = &current.Rules[j]
+					break
+				}
+			}
+		} else if i < len(unnamed) {
+			c = &unnamed[i]
+			i++
+		}
+		if c != nil && r.UnknownFields == nil {
+			t.Rules[k].UnknownFields = c.UnknownFields
+		}
+	}
 }
 
 type ValueTargetingRule struct {
+	// Name identifies the rule within its Value. It is only set on rules managed on their own,
+	// such as by edge_value_targeting_rule.
+	Name    string                 `json:"name,omitempty"`
 	Variant string                 `json:"variant"`
 	Spec    ValueTargetingRuleSpec `json:"spec"`
 	Expr    string                 `json:"expr"`
+
+	// UnknownFields holds the fields of the rule that ValueTargetingRule does not model.
+	UnknownFields map[string]json.RawMessage `json:"-"`
+}
+
+var targetingRuleFields = jsonFieldNames(reflect.TypeOf(ValueTargetingRule{}))
+
+func (r *ValueTargetingRule) UnmarshalJSON(b []byte) error {
+	type rule ValueTargetingRule
+	var err error
+	r.UnknownFields, err = unmarshalFields(b, (*rule)(r), targetingRuleFields)
+	return err
+}
+
+func (r ValueTargetingRule) MarshalJSON() ([]byte, error) {
+	type rule ValueTargetingRule
+	return marshalFields(rule(r), r.UnknownFields, targetingRuleFields)
 }
 
 type ValueTargetingRuleSpec int32
@@ -89,6 +388,23 @@ func TFValueTargetingRuleSpec(v ValueTargetingRuleSpec) string {
 type ValueTransform struct {
 	Spec ValueTransformSpec `json:"spec"`
 	Expr string             `json:"expr"`
+
+	// UnknownFields holds the fields of the transform that ValueTransform does not model.
+	UnknownFields map[string]json.RawMessage `json:"-"`
+}
+
+var transformFields = jsonFieldNames(reflect.TypeOf(ValueTransform{}))
+
+func (t *ValueTransform) UnmarshalJSON(b []byte) error {
+	type transform ValueTransform
+	var err error
+	t.UnknownFields, err = unmarshalFields(b, (*transform)(t), transformFields)
+	return err
+}
+
+func (t ValueTransform) MarshalJSON() ([]byte, error) {
+	type transform ValueTransform
+	return marshalFields(transform(t), t.UnknownFields, transformFields)
 }
 
 type ValueTransformSpec int32
@@ -122,3 +438,13 @@ type GetValueRequest struct {
 type DeleteValueRequest struct {
 	ID string `json:"id"`
 }
+
+type ListValuesRequest struct {
+	PageSize  int32  `json:"pageSize,omitempty"`
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+type ListValuesResponse struct {
+	Values        []*Value `json:"values"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
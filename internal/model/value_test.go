@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -55,3 +57,168 @@ func TestValueTransformSpecFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestValueEvaluation_Type(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		v    ValueEvaluation
+		want string
+	}{
+		{
+			v:    ValueEvaluation{BooleanValue: &ValueBooleanValue{}},
+			want: ValueTypeBoolean,
+		},
+		{
+			v:    ValueEvaluation{StringValue: &ValueStringValue{}},
+			want: ValueTypeString,
+		},
+		{
+			v:    ValueEvaluation{JSONValue: &ValueJSONValue{}},
+			want: ValueTypeJSON,
+		},
+		{
+			v:    ValueEvaluation{IntegerValue: &ValueIntegerValue{}},
+			want: ValueTypeInteger,
+		},
+		{
+			v:    ValueEvaluation{NumberValue: &ValueNumberValue{}},
+			want: ValueTypeNumber,
+		},
+		{
+			v:    ValueEvaluation{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			got := tt.v.Type()
+			if got != tt.want {
+				t.Fatalf("expected %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValue_Type(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		v    Value
+		want string
+	}{
+		{
+			v:    Value{},
+			want: "",
+		},
+		{
+			v: Value{Variants: ValueVariants{
+				"on":  {BooleanValue: &ValueBooleanValue{Value: true}},
+				"off": {BooleanValue: &ValueBooleanValue{}},
+			}},
+			want: ValueTypeBoolean,
+		},
+		{
+			v: Value{Variants: ValueVariants{
+				"on":   {BooleanValue: &ValueBooleanValue{Value: true}},
+				"json": {JSONValue: &ValueJSONValue{}},
+			}},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			got := tt.v.Type()
+			if got != tt.want {
+				t.Fatalf("expected %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValue_UnknownFields(t *testing.T) {
+	t.Parallel()
+	in := `{"id":"test","enabled":true,"defaultVariant":"on","owner":{"team":"growth"},"rollout":12345678901234567890}`
+
+	var v Value
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.ID != "test" || !v.Enabled || v.DefaultVariant != "on" {
+		t.Fatalf("unexpected value: %+v", v)
+	}
+	if len(v.UnknownFields) != 2 || string(v.UnknownFields["owner"]) != `{"team":"growth"}` {
+		t.Fatalf("unexpected unknown fields: %s", v.UnknownFields)
+	}
+
+	v.Description = "updated"
+	b, err := json.Marshal(&v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got["rollout"]) != "12345678901234567890" || string(got["owner"]) != `{"team":"growth"}` {
+		t.Fatalf("expected unknown fields to be written back, but got %s", b)
+	}
+	if string(got["description"]) != `"updated"` {
+		t.Fatalf("expected modeled fields to be written, but got %s", b)
+	}
+}
+
+func TestValue_NestedUnknownFields(t *testing.T) {
+	t.Parallel()
+	in := `{
+  "id": "test",
+  "variants": {"json": {"note": "a", "jsonValue": {"value": {"n": 1.0}, "schema": "s", "transforms": [{"spec": 0, "expr": "x", "label": "t"}]}}},
+  "targeting": {"mode": "first", "rules": [{"variant": "json", "expr": "a", "weight": 1}, {"name": "n", "variant": "json", "expr": "b", "weight": 2}]},
+  "tests": [{"variables": {}, "expected": "json", "title": "test"}]
+}`
+
+	var v Value
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := json.Marshal(&v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`"note":"a"`, `"schema":"s"`, `"label":"t"`, `"mode":"first"`, `"weight":1`, `"weight":2`, `"title":"test"`, `{"n":1.0}`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %s to be written back, but got %s", want, b)
+		}
+	}
+
+	// A Value built from scratch, with the named rule moved first, keeps them all.
+	next := Value{
+		ID: "test",
+		Variants: ValueVariants{"json": {JSONValue: &ValueJSONValue{
+			Value:      map[string]any{"n": 2},
+			Transforms: []*ValueTransform{{Expr: "y"}},
+		}}},
+		Targeting: ValueTargeting{Rules: []ValueTargetingRule{
+			{Name: "n", Variant: "json", Expr: "b"},
+			{Variant: "json", Expr: "c"},
+		}},
+		Tests: []*EvaluationTest{{Expected: "json"}},
+	}
+	next.KeepUnknownFields(&v)
+	b, err = json.Marshal(&next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`"note":"a"`, `"schema":"s"`, `"label":"t"`, `"mode":"first"`, `"title":"test"`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %s to be kept, but got %s", want, b)
+		}
+	}
+	if got := next.Targeting.Rules; string(got[0].UnknownFields["weight"]) != "2" || string(got[1].UnknownFields["weight"]) != "1" {
+		t.Errorf("expected the rules to keep their own fields, but got %s", b)
+	}
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &ValueDataSource{}
+	_ datasource.DataSourceWithConfigure = &ValueDataSource{}
+)
+
+func NewValueDataSource() datasource.DataSource {
+	return &ValueDataSource{}
+}
+
+type ValueDataSource struct {
+	c edgeclient.Client
+}
+
+type valueDataSourceModel struct {
+	ID             types.String                         `tfsdk:"id"`
+	ValueID        types.String                         `tfsdk:"value_id"`
+	Description    types.String                         `tfsdk:"description"`
+	Enabled        types.Bool                           `tfsdk:"enabled"`
+	DefaultVariant types.String                         `tfsdk:"default_variant"`
+	Type           types.String                         `tfsdk:"type"`
+	Variants       map[string]valueResourceVariantModel `tfsdk:"variants"`
+	Targeting      []valueResourceTargetingModel        `tfsdk:"targeting"`
+	Test           []valueResourceTestModel             `tfsdk:"test"`
+	CreateTime     types.String                         `tfsdk:"create_time"`
+	UpdateTime     types.String                         `tfsdk:"update_time"`
+}
+
+func (d *ValueDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_value"
+}
+
+func (d *ValueDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := valueDataSourceAttributes()
+	attributes["value_id"] = schema.StringAttribute{
+		Description: "The ID of the Value to look up.",
+		Required:    true,
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Edge value data source.",
+		Attributes:          attributes,
+	}
+}
+
+// valueDataSourceAttributes returns the read-only attributes describing a single value.
+func valueDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Computed ID.",
+			Computed:    true,
+		},
+		"value_id": schema.StringAttribute{
+			Description: "The ID of this Value.",
+			Computed:    true,
+		},
+		"description": schema.StringAttribute{
+			Computed: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Computed: true,
+		},
+		"default_variant": schema.StringAttribute{
+			Computed: true,
+		},
+		"type": schema.StringAttribute{
+			Description: "The type shared by every variant of this Value, or null when the variants mix types.",
+			Computed:    true,
+		},
+		"variants": schema.MapNestedAttribute{
+			Description: "The variants of this Value, keyed by variant name.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"boolean_value": schema.BoolAttribute{
+						Computed: true,
+					},
+					"string_value": schema.StringAttribute{
+						Computed: true,
+					},
+					"json_value": schema.StringAttribute{
+						CustomType: jsontypes.NormalizedType{},
+						Computed:   true,
+					},
+					"integer_value": schema.Int64Attribute{
+						Computed: true,
+					},
+					"number_value": schema.NumberAttribute{
+						Computed: true,
+					},
+					"transform": schema.ListNestedAttribute{
+						Computed: true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"spec": schema.StringAttribute{
+									Computed: true,
+								},
+								"expr": schema.StringAttribute{
+									Computed: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"targeting": schema.ListNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"variant": schema.StringAttribute{
+						Computed: true,
+					},
+					"spec": schema.StringAttribute{
+						Computed: true,
+					},
+					"expr": schema.StringAttribute{
+						Computed: true,
+					},
+				},
+			},
+		},
+		"test": schema.ListNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"variables": schema.StringAttribute{
+						CustomType: jsontypes.NormalizedType{},
+						Computed:   true,
+					},
+					"expected": schema.StringAttribute{
+						Computed: true,
+					},
+				},
+			},
+		},
+		"create_time": schema.StringAttribute{
+			Description: "Creation time in RFC3339 format.",
+			Computed:    true,
+		},
+		"update_time": schema.StringAttribute{
+			Description: "Last update time in RFC3339 format.",
+			Computed:    true,
+		},
+	}
+}
+
+func valueDataSourceState(v *model.Value) *valueDataSourceModel {
+	state := valueState(v)
+	return &valueDataSourceModel{
+		ID:             state.ID,
+		ValueID:        state.ValueID,
+		Description:    state.Description,
+		Enabled:        state.Enabled,
+		DefaultVariant: state.DefaultVariant,
+		Type:           state.Type,
+		Variants:       state.Variants,
+		Targeting:      state.Targeting,
+		Test:           state.Test,
+		CreateTime:     state.CreateTime,
+		UpdateTime:     state.UpdateTime,
+	}
+}
+
+func (d *ValueDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config valueDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, err := d.c.GetValue(ctx, config.ValueID.ValueString())
+	if edgeclient.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error reading value",
+			fmt.Sprintf("value %s does not exist", config.ValueID.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading value", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, valueDataSourceState(value))
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *ValueDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.c = req.ProviderData.(edgeclient.Client)
+}
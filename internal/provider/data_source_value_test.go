@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestAccDataSourceEdgeValue(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, integerTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccDataSourceValue(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.edge_value.test", "id", "test-integer-value"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "description", "test integer value"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "default_variant", "one"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "type", "integer"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "variants.%", "1"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "variants.one.integer_value", "1"),
+					resource.TestCheckNoResourceAttr("data.edge_value.test", "variants.one.boolean_value"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "targeting.#", "0"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "create_time", "2023-04-19T08:58:50Z"),
+					resource.TestCheckResourceAttr("data.edge_value.test", "update_time", "2023-04-21T15:08:54Z"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceValue() string {
+	return `
+data "edge_value" "test" {
+  value_id = "test-integer-value"
+}`
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &ValuesDataSource{}
+	_ datasource.DataSourceWithConfigure = &ValuesDataSource{}
+)
+
+func NewValuesDataSource() datasource.DataSource {
+	return &ValuesDataSource{}
+}
+
+type ValuesDataSource struct {
+	c edgeclient.Client
+}
+
+type valuesDataSourceModel struct {
+	ID             types.String            `tfsdk:"id"`
+	IDPrefix       types.String            `tfsdk:"id_prefix"`
+	Enabled        types.Bool              `tfsdk:"enabled"`
+	VariantType    types.String            `tfsdk:"variant_type"`
+	IncludeDetails types.Bool              `tfsdk:"include_details"`
+	ValueIDs       []types.String          `tfsdk:"value_ids"`
+	Values         []*valueDataSourceModel `tfsdk:"values"`
+}
+
+func (d *ValuesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_values"
+}
+
+func (d *ValuesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists Edge values, optionally filtered.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Computed ID.",
+				Computed:    true,
+			},
+			"id_prefix": schema.StringAttribute{
+				Description: "Only return values whose ID starts with this prefix.",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Only return values with this enabled state.",
+				Optional:    true,
+			},
+			"variant_type": schema.StringAttribute{
+				Description: "Only return values having a variant of this type. One of `boolean`, `string`, `json`, `integer` or `number`.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						model.ValueTypeBoolean,
+						model.ValueTypeString,
+						model.ValueTypeJSON,
+						model.ValueTypeInteger,
+						model.ValueTypeNumber,
+					),
+				},
+			},
+			"include_details": schema.BoolAttribute{
+				Description: "Populate `values` with the full definition of every matching value. Defaults to false.",
+				Optional:    true,
+			},
+			"value_ids": schema.ListAttribute{
+				Description: "IDs of the matching values, in the order returned by the Edge API.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"values": schema.ListNestedAttribute{
+				Description: "Matching values. Only populated when `include_details` is true.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: valueDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (m *valuesDataSourceModel) match(v *model.Value) bool {
+	if !m.IDPrefix.IsNull() && !strings.HasPrefix(v.ID, m.IDPrefix.ValueString()) {
+		return false
+	}
+	if !m.Enabled.IsNull() && v.Enabled != m.Enabled.ValueBool() {
+		return false
+	}
+	if !m.VariantType.IsNull() {
+		for _, e := range v.Variants {
+			if e.Type() == m.VariantType.ValueString() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (d *ValuesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state valuesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	values, err := edgeclient.ListAllValues(ctx, d.c)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing values", err.Error())
+		return
+	}
+
+	state.ID = types.StringValue("edge_values")
+	state.ValueIDs = make([]types.String, 0, len(values))
+	state.Values = make([]*valueDataSourceModel, 0, len(values))
+	for _, v := range values {
+		if !state.match(v) {
+			continue
+		}
+		state.ValueIDs = append(state.ValueIDs, types.StringValue(v.ID))
+		if state.IncludeDetails.ValueBool() {
+			state.Values = append(state.Values, valueDataSourceState(v))
+		}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *ValuesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.c = req.ProviderData.(edgeclient.Client)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestAccDataSourceEdgeValues(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/List",
+		httpmock.NewStringResponder(200, fmt.Sprintf(`{"values":[%s,%s,%s]}`, booleanTestdata, stringTestdata, integerTestdata)),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccDataSourceValues(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.edge_values.all", "value_ids.#", "3"),
+					resource.TestCheckResourceAttr("data.edge_values.all", "values.#", "0"),
+					resource.TestCheckResourceAttr("data.edge_values.prefix", "value_ids.#", "1"),
+					resource.TestCheckResourceAttr("data.edge_values.prefix", "value_ids.0", "test-integer-value"),
+					resource.TestCheckResourceAttr("data.edge_values.booleans", "value_ids.#", "1"),
+					resource.TestCheckResourceAttr("data.edge_values.booleans", "value_ids.0", "test-bool-value"),
+					resource.TestCheckResourceAttr("data.edge_values.disabled", "value_ids.#", "0"),
+					resource.TestCheckResourceAttr("data.edge_values.strings", "value_ids.#", "1"),
+					resource.TestCheckResourceAttr("data.edge_values.strings", "value_ids.0", "test-string-value"),
+					resource.TestCheckResourceAttr("data.edge_values.strings", "values.#", "1"),
+					resource.TestCheckResourceAttr("data.edge_values.strings", "values.0.default_variant", "key"),
+					resource.TestCheckResourceAttr("data.edge_values.strings", "values.0.variants.key.string_value", "test value"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceValues() string {
+	return `
+data "edge_values" "all" {}
+
+data "edge_values" "prefix" {
+  id_prefix = "test-int"
+}
+
+data "edge_values" "booleans" {
+  enabled = true
+  variant_type = "boolean"
+}
+
+data "edge_values" "disabled" {
+  enabled = false
+}
+
+data "edge_values" "strings" {
+  variant_type = "string"
+  include_details = true
+}`
+}
@@ -1,52 +1,51 @@
 package provider
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
-	"sync"
+	"time"
 
-	"github.com/ca-irvine/terraform-provider-edge/internal/model"
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	tffunc "github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-const (
-	headerKeyID       = "X-API-KEY-ID"
-	headerKey         = "X-API-KEY"
-	headerUA          = "User-Agent"
-	headerContentType = "Content-Type"
-)
-
-const (
-	applicationJSON = "application/json"
-)
-
 var _ provider.Provider = &EdgeProvider{}
 
 type EdgeProvider struct {
 	version string
-	config  *config
+	client  edgeclient.Client
 }
 
 type edgeProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	APIKeyID types.String `tfsdk:"api_key_id"`
-	APIKey   types.String `tfsdk:"api_key"`
+	Endpoint              types.String            `tfsdk:"endpoint"`
+	APIKeyID              types.String            `tfsdk:"api_key_id"`
+	APIKey                types.String            `tfsdk:"api_key"`
+	RequestTimeout        types.String            `tfsdk:"request_timeout"`
+	MaxConcurrentRequests types.Int64             `tfsdk:"max_concurrent_requests"`
+	Retry                 *edgeProviderRetryModel `tfsdk:"retry"`
 }
 
+type edgeProviderRetryModel struct {
+	MaxAttempts          types.Int64  `tfsdk:"max_attempts"`
+	MinWait              types.String `tfsdk:"min_wait"`
+	MaxWait              types.String `tfsdk:"max_wait"`
+	RetryableStatusCodes types.List   `tfsdk:"retryable_status_codes"`
+	RespectRetryAfter    types.Bool   `tfsdk:"respect_retry_after"`
+}
+
+const defaultRequestTimeout = 30 * time.Second
+
 func (p *EdgeProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "edge"
 }
@@ -66,6 +65,48 @@ func (p *EdgeProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Required:  true,
 				Sensitive: true,
 			},
+			"request_timeout": schema.StringAttribute{
+				Description: "Timeout for a single HTTP request to the Edge API, as a Go duration string (e.g. \"30s\"). Time spent waiting under `max_concurrent_requests` does not count. Defaults to \"30s\".",
+				Optional:    true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of Edge API requests in flight at once. Writes to the same value are always serialized. Unlimited by default.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				Description: "Retry policy for failed Edge API requests. Create requests are only retried when the server is known not to have processed them.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Description: "Total number of attempts per request, including the first one. Defaults to 6.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"min_wait": schema.StringAttribute{
+						Description: "Minimum wait between attempts, as a Go duration string. Defaults to \"1s\".",
+						Optional:    true,
+					},
+					"max_wait": schema.StringAttribute{
+						Description: "Maximum wait between attempts, as a Go duration string. Defaults to \"30s\".",
+						Optional:    true,
+					},
+					"retryable_status_codes": schema.ListAttribute{
+						Description: "HTTP status codes that are retried. Defaults to [429, 500, 502, 503, 504].",
+						ElementType: types.Int64Type,
+						Optional:    true,
+					},
+					"respect_retry_after": schema.BoolAttribute{
+						Description: "Wait for the Retry-After header of 429 and 503 responses, capped at max_wait. Defaults to true.",
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -155,6 +196,9 @@ func (p *EdgeProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		)
 	}
 
+	requestTimeout := parseDuration(path.Root("request_timeout"), cfg.RequestTimeout, defaultRequestTimeout, &resp.Diagnostics)
+	retry := retryPolicy(ctx, cfg.Retry, &resp.Diagnostics)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -166,33 +210,40 @@ func (p *EdgeProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 
 	tflog.Debug(ctx, "Creating Edge client")
 
-	if p.config == nil {
-		retryClient := retryablehttp.NewClient()
-		retryClient.RetryMax = 5
-		rc := retryClient.StandardClient()
-		p.config = &config{
-			m:        &sync.Mutex{},
-			ua:       "terraform-provider-edge",
-			keyID:    apiKeyID,
-			key:      apiKey,
-			endpoint: endpoint,
-			client:   rc,
-		}
+	if p.client == nil {
+		p.client = edgeclient.New(edgeclient.Config{
+			Endpoint:              endpoint,
+			APIKeyID:              apiKeyID,
+			APIKey:                apiKey,
+			UserAgent:             "terraform-provider-edge",
+			RequestTimeout:        requestTimeout,
+			Retry:                 &retry,
+			MaxConcurrentRequests: int(cfg.MaxConcurrentRequests.ValueInt64()),
+		})
 	}
 
-	resp.DataSourceData = p.config
-	resp.ResourceData = p.config
+	resp.DataSourceData = p.client
+	resp.ResourceData = p.client
 
 	tflog.Info(ctx, "Configured Edge client", map[string]any{"success": true})
 }
 
 func (p *EdgeProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewValueDataSource,
+		NewValuesDataSource,
+	}
 }
 
 func (p *EdgeProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewValueResource,
+		NewBooleanValueResource,
+		NewStringValueResource,
+		NewIntegerValueResource,
+		NewNumberValueResource,
+		NewJSONValueResource,
+		NewValueTargetingRuleResource,
 	}
 }
 
@@ -202,165 +253,59 @@ func (p *EdgeProvider) Functions(_ context.Context) []func() tffunc.Function {
 	}
 }
 
-func New(version string) func() provider.Provider {
-	return func() provider.Provider {
-		return &EdgeProvider{
-			version: version,
-		}
-	}
-}
-
-type config struct {
-	m        *sync.Mutex
-	ua       string
-	keyID    string
-	key      string
-	endpoint string
-	client   *http.Client
-}
-
-func (c *config) GetValue(ctx context.Context, id string) (*model.Value, error) {
-	const path = "/service.Value/Get"
-	u, err := url.JoinPath(c.endpoint, path)
-	if err != nil {
-		return nil, err
-	}
-
-	m := &model.GetValueRequest{ID: id}
-	j, err := json.Marshal(m)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(j))
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.do(ctx, req, false)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
-		b, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, string(b))
-	}
-	value := new(model.Value)
-	err = json.NewDecoder(resp.Body).Decode(value)
-	if err != nil {
-		return nil, err
-	}
-	return value, nil
-}
-
-func (c *config) CreateValue(ctx context.Context, value *model.Value) (*model.Value, error) {
-	const path = "/service.Value/Create"
-	u, err := url.JoinPath(c.endpoint, path)
-	if err != nil {
-		return nil, err
+func retryPolicy(ctx context.Context, cfg *edgeProviderRetryModel, diags *diag.Diagnostics) edgeclient.RetryPolicy {
+	policy := edgeclient.DefaultRetryPolicy()
+	if cfg == nil {
+		return policy
 	}
 
-	j, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(j))
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.do(ctx, req, true)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= http.StatusBadRequest {
-		b, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, string(b))
-	}
-	v := new(model.Value)
-	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
-		return nil, err
+	if !cfg.MaxAttempts.IsNull() && !cfg.MaxAttempts.IsUnknown() {
+		policy.MaxAttempts = int(cfg.MaxAttempts.ValueInt64())
 	}
-	return v, err
-}
-
-func (c *config) UpdateValue(ctx context.Context, value *model.Value) (*model.Value, error) {
-	const path = "/service.Value/Update"
-	u, err := url.JoinPath(c.endpoint, path)
-	if err != nil {
-		return nil, err
-	}
-
-	j, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(j))
-	if err != nil {
-		return nil, err
+	policy.MinWait = parseDuration(path.Root("retry").AtName("min_wait"), cfg.MinWait, policy.MinWait, diags)
+	policy.MaxWait = parseDuration(path.Root("retry").AtName("max_wait"), cfg.MaxWait, policy.MaxWait, diags)
+	if policy.MinWait > policy.MaxWait {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("min_wait"),
+			"Invalid Edge API Retry Policy",
+			fmt.Sprintf("min_wait (%s) must not be greater than max_wait (%s).", policy.MinWait, policy.MaxWait),
+		)
 	}
-
-	resp, err := c.do(ctx, req, true)
-	if err != nil {
-		return nil, err
+	if !cfg.RetryableStatusCodes.IsNull() && !cfg.RetryableStatusCodes.IsUnknown() {
+		var codes []int64
+		diags.Append(cfg.RetryableStatusCodes.ElementsAs(ctx, &codes, false)...)
+		policy.StatusCodes = make([]int, 0, len(codes))
+		for _, code := range codes {
+			policy.StatusCodes = append(policy.StatusCodes, int(code))
+		}
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= http.StatusBadRequest {
-		b, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, string(b))
+	if !cfg.RespectRetryAfter.IsNull() && !cfg.RespectRetryAfter.IsUnknown() {
+		policy.RespectRetryAfter = cfg.RespectRetryAfter.ValueBool()
 	}
-	v := new(model.Value)
-	err = json.NewDecoder(resp.Body).Decode(v)
-	return v, err
+	return policy
 }
 
-func (c *config) DeleteValue(ctx context.Context, id string) error {
-	const path = "/service.Value/Delete"
-	u, err := url.JoinPath(c.endpoint, path)
-	if err != nil {
-		return err
-	}
-
-	m := &model.DeleteValueRequest{ID: id}
-	j, err := json.Marshal(m)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(j))
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.do(ctx, req, true)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
-		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, string(b))
+// parseDuration parses a duration attribute, returning def when it is not set.
+func parseDuration(p path.Path, v types.String, def time.Duration, diags *diag.Diagnostics) time.Duration {
+	if v.IsNull() || v.IsUnknown() {
+		return def
+	}
+	d, err := time.ParseDuration(v.ValueString())
+	if err != nil || d <= 0 {
+		diags.AddAttributeError(
+			p,
+			"Invalid Duration",
+			fmt.Sprintf("The value %q must be a positive duration such as \"30s\" or \"1m\".", v.ValueString()),
+		)
+		return def
 	}
-	return nil
+	return d
 }
 
-func (c *config) do(ctx context.Context, req *http.Request, useMutex bool) (*http.Response, error) {
-	if useMutex {
-		c.m.Lock()
-		defer c.m.Unlock()
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &EdgeProvider{
+			version: version,
+		}
 	}
-	req.Header.Set(headerKeyID, c.keyID)
-	req.Header.Set(headerKey, c.key)
-	req.Header.Set(headerUA, c.ua)
-	req.Header.Set(headerContentType, applicationJSON)
-	req.WithContext(ctx)
-	res, err := c.client.Do(req)
-	return res, err
 }
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
@@ -15,11 +16,11 @@ provider "edge" {
 `
 )
 
-func protoV6ProviderFactories(cfg *config) map[string]func() (tfprotov6.ProviderServer, error) {
+func protoV6ProviderFactories(client edgeclient.Client) map[string]func() (tfprotov6.ProviderServer, error) {
 	return map[string]func() (tfprotov6.ProviderServer, error){
 		"edge": providerserver.NewProtocol6WithError(&EdgeProvider{
 			version: "test",
-			config:  cfg,
+			client:  client,
 		}),
 	}
 }
@@ -0,0 +1,630 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &TypedValueResource{}
+	_ resource.ResourceWithImportState    = &TypedValueResource{}
+	_ resource.ResourceWithValidateConfig = &TypedValueResource{}
+	_ resource.ResourceWithMoveState      = &TypedValueResource{}
+)
+
+func NewBooleanValueResource() resource.Resource {
+	return &TypedValueResource{kind: booleanValueKind}
+}
+
+func NewStringValueResource() resource.Resource {
+	return &TypedValueResource{kind: stringValueKind}
+}
+
+func NewIntegerValueResource() resource.Resource {
+	return &TypedValueResource{kind: integerValueKind}
+}
+
+func NewNumberValueResource() resource.Resource {
+	return &TypedValueResource{kind: numberValueKind}
+}
+
+func NewJSONValueResource() resource.Resource {
+	return &TypedValueResource{kind: jsonValueKind}
+}
+
+// TypedValueResource manages a Value whose variants all have the same type. Variants are
+// declared as a map of variant name to value and converted through valueResourceModel, so the
+// typed resources share the model.Value conversion with ValueResource.
+type TypedValueResource struct {
+	c    edgeclient.Client
+	kind *typedValueKind
+}
+
+type typedValueKind struct {
+	typ string
+	// variants returns the schema of the variants attribute.
+	variants func() schema.Attribute
+	// expand returns the edge_value variant of each element of the variants map.
+	expand func(ctx context.Context, variants types.Map) (map[string]valueResourceVariantModel, diag.Diagnostics)
+	// flatten returns the variants map holding the edge_value variants.
+	flatten func(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Map, diag.Diagnostics)
+}
+
+type (
+	typedValueResourceModel struct {
+		ID             types.String                  `tfsdk:"id"`
+		ValueID        types.String                  `tfsdk:"value_id"`
+		Description    types.String                  `tfsdk:"description"`
+		Enabled        types.Bool                    `tfsdk:"enabled"`
+		DefaultVariant types.String                  `tfsdk:"default_variant"`
+		Variants       types.Map                     `tfsdk:"variants"`
+		Targeting      []valueResourceTargetingModel `tfsdk:"targeting"`
+		Test           []valueResourceTestModel      `tfsdk:"test"`
+		CreateTime     types.String                  `tfsdk:"create_time"`
+		UpdateTime     types.String                  `tfsdk:"update_time"`
+		Timeouts       timeouts.Value                `tfsdk:"timeouts"`
+	}
+
+	typedJSONVariantModel struct {
+		Value     jsontypes.Normalized          `tfsdk:"value"`
+		Transform []valueResourceTransformModel `tfsdk:"transform"`
+	}
+)
+
+var booleanValueKind = &typedValueKind{
+	typ: model.ValueTypeBoolean,
+	variants: func() schema.Attribute {
+		return schema.MapAttribute{
+			Description: "The variants of this Value, keyed by variant name.",
+			ElementType: types.BoolType,
+			Required:    true,
+		}
+	},
+	expand: func(ctx context.Context, variants types.Map) (map[string]valueResourceVariantModel, diag.Diagnostics) {
+		return expandVariants(ctx, variants, func(v types.Bool) valueResourceVariantModel {
+			return valueResourceVariantModel{BooleanValue: v}
+		})
+	},
+	flatten: func(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Map, diag.Diagnostics) {
+		return flattenVariants(ctx, types.BoolType, variants, func(v valueResourceVariantModel) types.Bool {
+			return v.BooleanValue
+		})
+	},
+}
+
+var stringValueKind = &typedValueKind{
+	typ: model.ValueTypeString,
+	variants: func() schema.Attribute {
+		return schema.MapAttribute{
+			Description: "The variants of this Value, keyed by variant name.",
+			ElementType: types.StringType,
+			Required:    true,
+		}
+	},
+	expand: func(ctx context.Context, variants types.Map) (map[string]valueResourceVariantModel, diag.Diagnostics) {
+		return expandVariants(ctx, variants, func(v types.String) valueResourceVariantModel {
+			return valueResourceVariantModel{StringValue: v}
+		})
+	},
+	flatten: func(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Map, diag.Diagnostics) {
+		return flattenVariants(ctx, types.StringType, variants, func(v valueResourceVariantModel) types.String {
+			return v.StringValue
+		})
+	},
+}
+
+var integerValueKind = &typedValueKind{
+	typ: model.ValueTypeInteger,
+	variants: func() schema.Attribute {
+		return schema.MapAttribute{
+			Description: "The variants of this Value, keyed by variant name.",
+			ElementType: types.Int64Type,
+			Required:    true,
+		}
+	},
+	expand: func(ctx context.Context, variants types.Map) (map[string]valueResourceVariantModel, diag.Diagnostics) {
+		return expandVariants(ctx, variants, func(v types.Int64) valueResourceVariantModel {
+			return valueResourceVariantModel{IntegerValue: v}
+		})
+	},
+	flatten: func(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Map, diag.Diagnostics) {
+		return flattenVariants(ctx, types.Int64Type, variants, func(v valueResourceVariantModel) types.Int64 {
+			return v.IntegerValue
+		})
+	},
+}
+
+var numberValueKind = &typedValueKind{
+	typ: model.ValueTypeNumber,
+	variants: func() schema.Attribute {
+		return schema.MapAttribute{
+			Description: "The variants of this Value, keyed by variant name.",
+			ElementType: types.NumberType,
+			Required:    true,
+		}
+	},
+	expand: func(ctx context.Context, variants types.Map) (map[string]valueResourceVariantModel, diag.Diagnostics) {
+		return expandVariants(ctx, variants, func(v types.Number) valueResourceVariantModel {
+			return valueResourceVariantModel{NumberValue: v}
+		})
+	},
+	flatten: func(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Map, diag.Diagnostics) {
+		return flattenVariants(ctx, types.NumberType, variants, func(v valueResourceVariantModel) types.Number {
+			return v.NumberValue
+		})
+	},
+}
+
+var jsonValueKind = &typedValueKind{
+	typ: model.ValueTypeJSON,
+	variants: func() schema.Attribute {
+		return jsonVariantsAttribute()
+	},
+	expand: func(ctx context.Context, variants types.Map) (map[string]valueResourceVariantModel, diag.Diagnostics) {
+		return expandVariants(ctx, variants, func(v typedJSONVariantModel) valueResourceVariantModel {
+			return valueResourceVariantModel{JSONValue: v.Value, Transform: v.Transform}
+		})
+	},
+	flatten: func(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Map, diag.Diagnostics) {
+		return flattenVariants(ctx, jsonVariantsAttribute().NestedObject.Type(), variants, func(v valueResourceVariantModel) typedJSONVariantModel {
+			return typedJSONVariantModel{Value: v.JSONValue, Transform: v.Transform}
+		})
+	},
+}
+
+func jsonVariantsAttribute() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		Description: "The variants of this Value, keyed by variant name.",
+		Required:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"value": schema.StringAttribute{
+					Description: "A JSON document. Objects, arrays and scalars are all accepted.",
+					CustomType:  jsontypes.NormalizedType{},
+					Required:    true,
+				},
+				"transform": schema.ListNestedAttribute{
+					Optional: true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"spec": schema.StringAttribute{
+								Optional: true,
+							},
+							"expr": schema.StringAttribute{
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// expandVariants converts each element of a variants map into an edge_value variant.
+func expandVariants[V any](ctx context.Context, variants types.Map, variant func(V) valueResourceVariantModel) (map[string]valueResourceVariantModel, diag.Diagnostics) {
+	elems := make(map[string]V, len(variants.Elements()))
+	diags := variants.ElementsAs(ctx, &elems, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+	out := make(map[string]valueResourceVariantModel, len(elems))
+	for k, v := range elems {
+		out[k] = variant(v)
+	}
+	return out, diags
+}
+
+// flattenVariants converts edge_value variants into a variants map.
+func flattenVariants[V any](ctx context.Context, elemType attr.Type, variants map[string]valueResourceVariantModel, elem func(valueResourceVariantModel) V) (types.Map, diag.Diagnostics) {
+	elems := make(map[string]V, len(variants))
+	for k, v := range variants {
+		elems[k] = elem(v)
+	}
+	return types.MapValueFrom(ctx, elemType, elems)
+}
+
+// valueModel converts m into the generic edge_value model.
+func (m *typedValueResourceModel) valueModel(ctx context.Context, kind *typedValueKind) (*valueResourceModel, diag.Diagnostics) {
+	value := &valueResourceModel{
+		ID:             m.ID,
+		ValueID:        m.ValueID,
+		Description:    m.Description,
+		Enabled:        m.Enabled,
+		DefaultVariant: m.DefaultVariant,
+		Type:           types.StringValue(kind.typ),
+		Targeting:      m.Targeting,
+		Test:           m.Test,
+		CreateTime:     m.CreateTime,
+		UpdateTime:     m.UpdateTime,
+		Timeouts:       m.Timeouts,
+	}
+	var diags diag.Diagnostics
+	value.Variants, diags = kind.expand(ctx, m.Variants)
+	return value, diags
+}
+
+// typedValueState converts the generic edge_value model m into the model of kind. It fails
+// when m holds variants of another type.
+func typedValueState(ctx context.Context, kind *typedValueKind, m *valueResourceModel) (*typedValueResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	for _, typ := range m.variantTypes() {
+		if typ != kind.typ {
+			diags.AddError(
+				"Incompatible Value Type",
+				fmt.Sprintf("Value %s has %s variants and cannot be managed as a %s value.", m.ValueID.ValueString(), typ, kind.typ),
+			)
+			return nil, diags
+		}
+	}
+
+	variants, diags := kind.flatten(ctx, m.Variants)
+	return &typedValueResourceModel{
+		ID:             m.ID,
+		ValueID:        m.ValueID,
+		Description:    m.Description,
+		Enabled:        m.Enabled,
+		DefaultVariant: m.DefaultVariant,
+		Variants:       variants,
+		Targeting:      m.Targeting,
+		Test:           m.Test,
+		CreateTime:     m.CreateTime,
+		UpdateTime:     m.UpdateTime,
+		Timeouts:       m.Timeouts,
+	}, diags
+}
+
+func (r *TypedValueResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.kind.typ + "_value"
+}
+
+func (r *TypedValueResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: fmt.Sprintf("Edge value resource whose variants are all of type `%s`.", r.kind.typ),
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Computed ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"value_id": schema.StringAttribute{
+				Description: "The ID of this Value.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"enabled": schema.BoolAttribute{
+				Required: true,
+			},
+			"default_variant": schema.StringAttribute{
+				Required: true,
+			},
+			"create_time": createTimeAttribute(),
+			"update_time": updateTimeAttribute(),
+			"variants":    r.kind.variants(),
+		},
+		Blocks: map[string]schema.Block{
+			"targeting": targetingBlock(),
+			"test":      testBlock(),
+			"timeouts":  valueTimeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *TypedValueResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateTargeting(ctx, req.Config, &resp.Diagnostics)
+
+	validateTransforms(ctx, req.Config, "value", &resp.Diagnostics)
+	validateVariantReferences(ctx, req.Config, &resp.Diagnostics)
+
+	if !resp.Diagnostics.HasError() {
+		runValueTests(ctx, req.Config, &resp.Diagnostics)
+	}
+}
+
+func (r *TypedValueResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	value, err := r.c.GetValue(ctx, req.ID)
+	if edgeclient.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error importing value",
+			fmt.Sprintf("value %s does not exist", req.ID),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error get value", err.Error())
+		return
+	}
+
+	state, diags := typedValueState(ctx, r.kind, valueState(withoutNamedRules(value)))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *TypedValueResource) MoveState(ctx context.Context) []resource.StateMover {
+	var source resource.SchemaResponse
+	(&ValueResource{}).Schema(ctx, resource.SchemaRequest{}, &source)
+	sourceV0 := valueResourceSchemaV0(ctx)
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &sourceV0,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "edge_value" || req.SourceSchemaVersion != 0 || req.SourceState == nil {
+					return
+				}
+
+				var value valueResourceModelV0
+				diags := req.SourceState.Get(ctx, &value)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				r.moveValueState(ctx, value.upgrade(), resp)
+			},
+		},
+		{
+			SourceSchema: &source.Schema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "edge_value" || req.SourceSchemaVersion != source.Schema.Version || req.SourceState == nil {
+					return
+				}
+
+				var value valueResourceModel
+				diags := req.SourceState.Get(ctx, &value)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				r.moveValueState(ctx, &value, resp)
+			},
+		},
+	}
+}
+
+// moveValueState sets the target state of resp from the edge_value state value.
+func (r *TypedValueResource) moveValueState(ctx context.Context, value *valueResourceModel, resp *resource.MoveStateResponse) {
+	if !value.VariantValues.IsNull() {
+		// The variants of an edge_value declared through variant_values are only kept there.
+		variants, _, err := expandVariantValues(ctx, value.VariantValues, r.kind.typ)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Variant Values", err.Error())
+			return
+		}
+		value.Variants = variants
+	}
+	state, diags := typedValueState(ctx, r.kind, value)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = resp.TargetState.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *TypedValueResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan typedValueResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	value, ok := r.value(ctx, &plan, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	value, err := r.c.CreateValue(ctx, value)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating value", err.Error())
+		return
+	}
+
+	state, ok := r.state(ctx, &plan, value, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, value)...)
+}
+
+func (r *TypedValueResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state typedValueResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	value, err := r.c.GetValue(ctx, state.ID.ValueString())
+	if edgeclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Value not found, removing from state", map[string]any{"value_id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading value", err.Error())
+		return
+	}
+
+	newState, ok := r.state(ctx, &state, value, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	diags = resp.State.Set(ctx, newState)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, value)...)
+}
+
+func (r *TypedValueResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan typedValueResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	value, ok := r.value(ctx, &plan, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	revision, diags := getRevision(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The update is checked against the current Value and conditioned on its revision, as
+	// edge_value does.
+	unlock := valueLocks.Lock(plan.ID.ValueString())
+	defer unlock()
+	var prior typedValueResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pm, diags := prior.valueModel(ctx, r.kind)
+	resp.Diagnostics.Append(diags...)
+	vm, diags := plan.valueModel(ctx, r.kind)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	revision, ok = currentRevision(ctx, r.c, vm, pm, value, revision, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	value, err := r.c.UpdateValue(ctx, value, revision)
+	if edgeclient.IsConflict(err) {
+		addConflictError(ctx, r.c, plan.ID.ValueString(), revision, err, &resp.Diagnostics)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating value", err.Error())
+		return
+	}
+
+	state, ok := r.state(ctx, &plan, value, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, value)...)
+}
+
+func (r *TypedValueResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state typedValueResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.c.DeleteValue(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting value", err.Error())
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *TypedValueResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.c = req.ProviderData.(edgeclient.Client)
+}
+
+// value converts m into the model.Value sent to the Edge API.
+func (r *TypedValueResource) value(ctx context.Context, m *typedValueResourceModel, diags *diag.Diagnostics) (*model.Value, bool) {
+	vm, d := m.valueModel(ctx, r.kind)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, false
+	}
+	value, err := vm.value(ctx)
+	if err != nil {
+		diags.AddError("Invalid Value", "Invalid Attribute(s): "+err.Error())
+		return nil, false
+	}
+	return value, true
+}
+
+// state returns the state of the Value v returned by the Edge API, keeping the values of prior
+// that are semantically equal as reconcileValueState does.
+func (r *TypedValueResource) state(ctx context.Context, prior *typedValueResourceModel, v *model.Value, diags *diag.Diagnostics) (*typedValueResourceModel, bool) {
+	pm, d := prior.valueModel(ctx, r.kind)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, false
+	}
+	reconciled, d := reconcileValueState(ctx, pm, v)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, false
+	}
+	state, d := typedValueState(ctx, r.kind, reconciled)
+	diags.Append(d...)
+	return state, !diags.HasError()
+}
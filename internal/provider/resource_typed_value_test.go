@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestAccResourceEdgeBooleanValue(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceTypedBoolean(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "value_id", "test-bool-value"),
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "default_variant", "off"),
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "variants.%", "2"),
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "variants.on", "true"),
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "variants.off", "false"),
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "targeting.#", "2"),
+				),
+			},
+			{
+				ResourceName:            "edge_boolean_value.test-bool-value",
+				ImportState:             true,
+				ImportStateId:           "test-bool-value",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeJSONValue(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, jsonTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, jsonTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, jsonTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceTypedJSON(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_json_value.test-json-value", "variants.%", "1"),
+					resource.TestCheckResourceAttr("edge_json_value.test-json-value", "variants.json.transform.#", "2"),
+					resource.TestCheckResourceAttr("edge_json_value.test-json-value", "variants.json.transform.0.spec", "cel"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeBooleanValue_Moved(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceBoolean(),
+			},
+			{
+				Config: providerConfig + testAccResourceTypedBoolean() + `
+moved {
+  from = edge_value.test-bool-value
+  to   = edge_boolean_value.test-bool-value
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "variants.on", "true"),
+					resource.TestCheckResourceAttr("edge_boolean_value.test-bool-value", "variants.off", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestTypedValueResource_MoveValueStateVariantValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := &TypedValueResource{kind: booleanValueKind}
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	resp := &fwresource.MoveStateResponse{
+		TargetState: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+	value := &valueResourceModel{
+		ID:             types.StringValue("test-bool-value"),
+		ValueID:        types.StringValue("test-bool-value"),
+		Enabled:        types.BoolValue(true),
+		DefaultVariant: types.StringValue("off"),
+		VariantValues: dynamicObject(t, map[string]tftypes.Value{
+			"on":  tftypes.NewValue(tftypes.Bool, true),
+			"off": tftypes.NewValue(tftypes.Bool, false),
+		}),
+		Timeouts: nullTimeouts(),
+	}
+
+	r.moveValueState(ctx, value, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	var variants map[string]bool
+	if diags := resp.TargetState.GetAttribute(ctx, path.Root("variants"), &variants); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if want := map[string]bool{"on": true, "off": false}; !reflect.DeepEqual(variants, want) {
+		t.Fatalf("expected variants %v, but got %v", want, variants)
+	}
+}
+
+func TestAccResourceEdgeStringValue_UndeclaredVariant(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "edge_string_value" "test" {
+  value_id = "test"
+  enabled = true
+  default_variant = "missing"
+  variants = {
+    key = "test value"
+  }
+}`,
+				ExpectError: regexp.MustCompile(`(?s)Undeclared Variant.*default_variant refers to variant "missing"`),
+			},
+		},
+	})
+}
+
+func testAccResourceTypedBoolean() string {
+	return `
+resource "edge_boolean_value" "test-bool-value" {
+  value_id = "test-bool-value"
+  enabled = true
+  description = "test bool value"
+  default_variant = "off"
+
+  variants = {
+    on  = true
+    off = false
+  }
+
+  targeting {
+    variant = "on"
+    spec = "cel"
+    expr = "env == 'dev'"
+  }
+
+  targeting {
+    variant = "on"
+    spec = "cel"
+    expr = "userId == 'XXX'"
+  }
+
+  test {
+	variables = jsonencode({
+	  env = "dev"
+	  count = 1
+	})
+	expected = "on"
+  }
+}`
+}
+
+func testAccResourceTypedJSON() string {
+	return `
+resource "edge_json_value" "test-json-value" {
+  value_id = "test-json-value"
+  enabled = true
+  description = "test json value"
+  default_variant = "json"
+
+  variants = {
+    json = {
+      value = jsonencode({
+        "items": [
+          {"viewable": true, "content": "content1"},
+          {"viewable": true, "content": "content2"},
+          {"viewable": false, "content": "content3"}
+        ]
+      })
+      transform = [
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"
+        },
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"
+        },
+      ]
+    }
+  }
+}`
+}
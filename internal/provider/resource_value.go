@@ -3,65 +3,73 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"regexp"
+	"errors"
+	"fmt"
+	"math/big"
 	"strconv"
+	"time"
 
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
 	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
 	_ resource.Resource = &ValueResource{}
 )
 
+const defaultValueTimeout = 20 * time.Minute
+
+// valueLocks serializes the read-modify-write updates of a Value by this provider, which the
+// one-second resolution of its revision cannot tell apart. It is separate from the locks of
+// edgeclient, which are held only for the duration of a single call.
+var valueLocks = edgeclient.NewKeyedMutex()
+
 func NewValueResource() resource.Resource {
 	return &ValueResource{}
 }
 
 type ValueResource struct {
-	c *config
+	c edgeclient.Client
 }
 
 type (
 	valueResourceModel struct {
-		ID             types.String                     `tfsdk:"id"`
-		ValueID        types.String                     `tfsdk:"value_id"`
-		Description    types.String                     `tfsdk:"description"`
-		Enabled        types.Bool                       `tfsdk:"enabled"`
-		DefaultVariant types.String                     `tfsdk:"default_variant"`
-		BooleanValue   []valueResourceBooleanValueModel `tfsdk:"boolean_value"`
-		StringValue    []valueResourceStringValueModel  `tfsdk:"string_value"`
-		JSONValue      []valueResourceJSONValueModel    `tfsdk:"json_value"`
-		IntegerValue   []valueResourceIntegerValueModel `tfsdk:"integer_value"`
-		Targeting      []valueResourceTargetingModel    `tfsdk:"targeting"`
-		Test           []valueResourceTestModel         `tfsdk:"test"`
-	}
-
-	valueResourceBooleanValueModel struct {
-		Variant types.String `tfsdk:"variant"`
-		Value   types.Bool   `tfsdk:"value"`
-	}
-
-	valueResourceStringValueModel struct {
-		Variant types.String `tfsdk:"variant"`
-		Value   types.String `tfsdk:"value"`
+		ID             types.String                         `tfsdk:"id"`
+		ValueID        types.String                         `tfsdk:"value_id"`
+		Description    types.String                         `tfsdk:"description"`
+		Enabled        types.Bool                           `tfsdk:"enabled"`
+		DefaultVariant types.String                         `tfsdk:"default_variant"`
+		Type           types.String                         `tfsdk:"type"`
+		Variants       map[string]valueResourceVariantModel `tfsdk:"variants"`
+		VariantValues  types.Dynamic                        `tfsdk:"variant_values"`
+		Targeting      []valueResourceTargetingModel        `tfsdk:"targeting"`
+		Test           []valueResourceTestModel             `tfsdk:"test"`
+		ManagedFields  types.Set                            `tfsdk:"managed_fields"`
+		CreateTime     types.String                         `tfsdk:"create_time"`
+		UpdateTime     types.String                         `tfsdk:"update_time"`
+		Timeouts       timeouts.Value                       `tfsdk:"timeouts"`
 	}
 
-	valueResourceJSONValueModel struct {
-		Variant   types.String                  `tfsdk:"variant"`
-		Value     types.String                  `tfsdk:"value"`
-		Transform []valueResourceTransformModel `tfsdk:"transform"`
-	}
-
-	valueResourceIntegerValueModel struct {
-		Variant types.String `tfsdk:"variant"`
-		Value   types.Int64  `tfsdk:"value"`
+	valueResourceVariantModel struct {
+		BooleanValue types.Bool                    `tfsdk:"boolean_value"`
+		StringValue  types.String                  `tfsdk:"string_value"`
+		JSONValue    jsontypes.Normalized          `tfsdk:"json_value"`
+		IntegerValue types.Int64                   `tfsdk:"integer_value"`
+		NumberValue  types.Number                  `tfsdk:"number_value"`
+		Transform    []valueResourceTransformModel `tfsdk:"transform"`
 	}
 
 	valueResourceTargetingModel struct {
@@ -71,8 +79,8 @@ type (
 	}
 
 	valueResourceTestModel struct {
-		Variables types.String `tfsdk:"variables"`
-		Expected  types.String `tfsdk:"expected"`
+		Variables jsontypes.Normalized `tfsdk:"variables"`
+		Expected  types.String         `tfsdk:"expected"`
 	}
 
 	valueResourceTransformModel struct {
@@ -83,12 +91,19 @@ type (
 
 func (v *ValueResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	value, err := v.c.GetValue(ctx, req.ID)
+	if edgeclient.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error importing value",
+			fmt.Sprintf("value %s does not exist", req.ID),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Error get value", err.Error())
 		return
 	}
 
-	state := valueState(value)
+	state := valueState(withoutNamedRules(value))
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
@@ -97,9 +112,10 @@ func (v *ValueResource) Metadata(_ context.Context, req resource.MetadataRequest
 	resp.TypeName = req.ProviderTypeName + "_value"
 }
 
-func (v *ValueResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (v *ValueResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Edge value resource.",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Computed ID.",
@@ -122,53 +138,70 @@ func (v *ValueResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Required: true,
 			},
 			"default_variant": schema.StringAttribute{
-				Required: true,
+				Description: "Required unless `managed_fields` leaves `variants` to be managed elsewhere.",
+				Optional:    true,
 			},
-		},
-		Blocks: map[string]schema.Block{
-			"boolean_value": schema.ListNestedBlock{
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"variant": schema.StringAttribute{
-							Required: true,
-						},
-						"value": schema.BoolAttribute{
-							Required: true,
-						},
-					},
+			"managed_fields": schema.SetAttribute{
+				Description: "The fields of this Value owned by this resource: `variants`, which covers `variants`, `variant_values` and `default_variant`, and `targeting`. Fields left out are managed elsewhere, such as in the Edge UI: they must not be set, are never diffed, and are kept as they are on update. A Value whose `variants` are managed elsewhere must exist already and be imported. Defaults to all fields.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.OneOf(managedFieldVariants, managedFieldTargeting),
+					),
 				},
 			},
-			"string_value": schema.ListNestedBlock{
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"variant": schema.StringAttribute{
-							Required: true,
-						},
-						"value": schema.StringAttribute{
-							Required: true,
-						},
-					},
+			"create_time": createTimeAttribute(),
+			"update_time": updateTimeAttribute(),
+			"type": schema.StringAttribute{
+				Description: "The type of every variant of this Value. One of `boolean`, `string`, `json`, `integer` or `number`. Inferred from the variants when omitted. Changing it forces a new Value.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						model.ValueTypeBoolean,
+						model.ValueTypeString,
+						model.ValueTypeJSON,
+						model.ValueTypeInteger,
+						model.ValueTypeNumber,
+					),
+				},
+				PlanModifiers: []planmodifier.String{
+					inferValueType(),
+					stringplanmodifier.RequiresReplace(),
 				},
 			},
-			"json_value": schema.ListNestedBlock{
-				NestedObject: schema.NestedBlockObject{
+			"variant_values": schema.DynamicAttribute{
+				Description: "The variants of this Value as native values, keyed by variant name, instead of `variants`. Objects and lists become `json` variants, bools and strings `boolean` and `string` variants. Numbers become `integer` variants when they are all whole and `type` is not `number`, and `number` variants otherwise. Transforms are not supported.",
+				Optional:    true,
+			},
+			"variants": schema.MapNestedAttribute{
+				Description: "The variants of this Value, keyed by variant name. Each variant sets exactly one of `boolean_value`, `string_value`, `json_value`, `integer_value` or `number_value`. Required unless `variant_values` is set or `managed_fields` leaves `variants` to be managed elsewhere.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
-						"variant": schema.StringAttribute{
-							Required: true,
+						"boolean_value": schema.BoolAttribute{
+							Optional: true,
 						},
-						"value": schema.StringAttribute{
-							Required: true,
-							Validators: []validator.String{
-								stringvalidator.RegexMatches(
-									regexp.MustCompile(`^{.*}$`),
-									"Must be map object, not array",
-								),
-							},
+						"string_value": schema.StringAttribute{
+							Optional: true,
 						},
-					},
-					Blocks: map[string]schema.Block{
-						"transform": schema.ListNestedBlock{
-							NestedObject: schema.NestedBlockObject{
+						"json_value": schema.StringAttribute{
+							Description: "A JSON document. Objects, arrays and scalars are all accepted.",
+							CustomType:  jsontypes.NormalizedType{},
+							Optional:    true,
+						},
+						"integer_value": schema.Int64Attribute{
+							Optional: true,
+						},
+						"number_value": schema.NumberAttribute{
+							Description: "A decimal number, kept exactly as written.",
+							Optional:    true,
+						},
+						"transform": schema.ListNestedAttribute{
+							Description: "Transforms applied to `json_value`. A transform of an object must evaluate to an object, and a transform of an array to an array.",
+							Optional:    true,
+							NestedObject: schema.NestedAttributeObject{
 								Attributes: map[string]schema.Attribute{
 									"spec": schema.StringAttribute{
 										Optional: true,
@@ -182,91 +215,102 @@ func (v *ValueResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					},
 				},
 			},
-			"integer_value": schema.ListNestedBlock{
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"variant": schema.StringAttribute{
-							Required: true,
-						},
-						"value": schema.Int64Attribute{
-							Required: true,
-						},
-					},
+		},
+		Blocks: map[string]schema.Block{
+			"targeting": targetingBlock(),
+			"test":      testBlock(),
+			"timeouts":  valueTimeoutsBlock(ctx),
+		},
+	}
+}
+
+// createTimeAttribute returns the create_time attribute shared by the value resources.
+func createTimeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "Creation time in RFC3339 format.",
+		Computed:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// updateTimeAttribute returns the update_time attribute shared by the value resources.
+func updateTimeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "Last update time in RFC3339 format. It changes on every write and serves as the revision of the Value: an update is rejected if the Value was changed since Terraform last read it. As it only has a resolution of one second, the fields Terraform manages are also compared before every update.",
+		Computed:    true,
+	}
+}
+
+// targetingBlock returns the targeting block shared by the value resources.
+func targetingBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "The targeting rules of this Value, evaluated in order. The named rules managed by `edge_value_targeting_rule` are not listed here and are kept in place on update.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"variant": schema.StringAttribute{
+					Required: true,
 				},
-			},
-			"targeting": schema.ListNestedBlock{
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"variant": schema.StringAttribute{
-							Required: true,
-						},
-						"spec": schema.StringAttribute{
-							Optional: true,
-						},
-						"expr": schema.StringAttribute{
-							Required: true,
-						},
-					},
+				"spec": schema.StringAttribute{
+					Optional: true,
 				},
-			},
-			"test": schema.ListNestedBlock{
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"variables": schema.StringAttribute{
-							Required: true,
-						},
-						"expected": schema.StringAttribute{
-							Required: true,
-						},
-					},
+				"expr": schema.StringAttribute{
+					Required: true,
 				},
 			},
 		},
 	}
 }
 
-func (v *valueResourceModel) value() (*model.Value, error) {
-	variants := model.ValueVariants{}
-	for _, val := range v.BooleanValue {
-		variants[val.Variant.ValueString()] = model.ValueEvaluation{
-			BooleanValue: &model.ValueBooleanValue{
-				Value: val.Value.ValueBool(),
-			},
-		}
-	}
-	for _, val := range v.StringValue {
-		variants[val.Variant.ValueString()] = model.ValueEvaluation{
-			StringValue: &model.ValueStringValue{
-				Value: val.Value.ValueString(),
+// testBlock returns the test block shared by the value resources.
+func testBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "Expected evaluations of this Value, checked against its CEL targeting rules when planning. A rule that fails to evaluate, for example because it references a variable the test does not set, is treated as not matching.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"variables": schema.StringAttribute{
+					CustomType: jsontypes.NormalizedType{},
+					Required:   true,
+				},
+				"expected": schema.StringAttribute{
+					Required: true,
+				},
 			},
-		}
+		},
 	}
-	for _, val := range v.JSONValue {
-		m := make(map[string]any)
-		err := json.Unmarshal([]byte(val.Value.ValueString()), &m)
+}
+
+// valueTimeoutsBlock returns the timeouts block shared by the value resources.
+func valueTimeoutsBlock(ctx context.Context) schema.Block {
+	return timeouts.Block(ctx, timeouts.Opts{
+		Create: true,
+		Read:   true,
+		Update: true,
+		Delete: true,
+	})
+}
+
+func (v *valueResourceModel) value(ctx context.Context) (*model.Value, error) {
+	declared := v.Variants
+	if !v.VariantValues.IsNull() {
+		expanded, known, err := expandVariantValues(ctx, v.VariantValues, v.Type.ValueString())
 		if err != nil {
 			return nil, err
 		}
-		transforms := make([]*model.ValueTransform, 0, len(val.Transform))
-		for _, t := range val.Transform {
-			transforms = append(transforms, &model.ValueTransform{
-				Spec: model.ValueTransformSpecFrom(t.Spec.ValueString()),
-				Expr: t.Expr.ValueString(),
-			})
-		}
-		variants[val.Variant.ValueString()] = model.ValueEvaluation{
-			JSONValue: &model.ValueJSONValue{
-				Value:      m,
-				Transforms: transforms,
-			},
+		if !known {
+			return nil, errors.New("variant_values is not known")
 		}
+		declared = expanded
 	}
-	for _, val := range v.IntegerValue {
-		variants[val.Variant.ValueString()] = model.ValueEvaluation{
-			IntegerValue: &model.ValueIntegerValue{
-				Value: json.Number(strconv.Itoa(int(val.Value.ValueInt64()))),
-			},
+
+	variants := make(model.ValueVariants, len(declared))
+	for k, val := range declared {
+		e, err := val.evaluation()
+		if err != nil {
+			return nil, fmt.Errorf("variant %s: %w", k, err)
 		}
+		variants[k] = e
 	}
 
 	rules := make([]model.ValueTargetingRule, 0, len(v.Targeting))
@@ -304,66 +348,114 @@ func (v *valueResourceModel) value() (*model.Value, error) {
 	return value, nil
 }
 
-func valueState(v *model.Value) *valueResourceModel {
-	var (
-		bools []valueResourceBooleanValueModel
-		strs  []valueResourceStringValueModel
-		jsons []valueResourceJSONValueModel
-		ints  []valueResourceIntegerValueModel
-	)
-
-	for k, val := range v.Variants {
-		if val.BooleanValue != nil {
-			if bools == nil {
-				bools = make([]valueResourceBooleanValueModel, 0, len(v.Variants))
-			}
-			bools = append(bools, valueResourceBooleanValueModel{
-				Variant: types.StringValue(k),
-				Value:   types.BoolValue(val.BooleanValue.Value),
-			})
+// evaluation converts v into the model variant of its type.
+func (v valueResourceVariantModel) evaluation() (model.ValueEvaluation, error) {
+	switch {
+	case !v.BooleanValue.IsNull():
+		return model.ValueEvaluation{
+			BooleanValue: &model.ValueBooleanValue{
+				Value: v.BooleanValue.ValueBool(),
+			},
+		}, nil
+	case !v.StringValue.IsNull():
+		return model.ValueEvaluation{
+			StringValue: &model.ValueStringValue{
+				Value: v.StringValue.ValueString(),
+			},
+		}, nil
+	case !v.JSONValue.IsNull():
+		doc, err := jsontypes.Decode(v.JSONValue.ValueString())
+		if err != nil {
+			return model.ValueEvaluation{}, err
 		}
-		if val.StringValue != nil {
-			if strs == nil {
-				strs = make([]valueResourceStringValueModel, 0, len(v.Variants))
-			}
-			strs = append(strs, valueResourceStringValueModel{
-				Variant: types.StringValue(k),
-				Value:   types.StringValue(val.StringValue.Value),
+		transforms := make([]*model.ValueTransform, 0, len(v.Transform))
+		for _, t := range v.Transform {
+			transforms = append(transforms, &model.ValueTransform{
+				Spec: model.ValueTransformSpecFrom(t.Spec.ValueString()),
+				Expr: t.Expr.ValueString(),
 			})
 		}
-		if val.JSONValue != nil {
-			if jsons == nil {
-				jsons = make([]valueResourceJSONValueModel, 0, len(v.Variants))
-			}
-			b, _ := json.Marshal(val.JSONValue.Value)
-			transforms := make([]valueResourceTransformModel, 0, len(val.JSONValue.Transforms))
-			for _, t := range val.JSONValue.Transforms {
-				transforms = append(transforms, valueResourceTransformModel{
-					Spec: types.StringValue(model.TFValueTransformSpec(t.Spec)),
-					Expr: types.StringValue(t.Expr),
-				})
-			}
-			jsons = append(jsons, valueResourceJSONValueModel{
-				Variant:   types.StringValue(k),
-				Value:     types.StringValue(string(b)),
-				Transform: transforms,
+		return model.ValueEvaluation{
+			JSONValue: &model.ValueJSONValue{
+				Value:      doc,
+				Transforms: transforms,
+			},
+		}, nil
+	case !v.IntegerValue.IsNull():
+		return model.ValueEvaluation{
+			IntegerValue: &model.ValueIntegerValue{
+				Value: json.Number(strconv.FormatInt(v.IntegerValue.ValueInt64(), 10)),
+			},
+		}, nil
+	case !v.NumberValue.IsNull():
+		return model.ValueEvaluation{
+			NumberValue: &model.ValueNumberValue{
+				Value: json.Number(v.NumberValue.ValueBigFloat().Text('g', -1)),
+			},
+		}, nil
+	default:
+		return model.ValueEvaluation{}, errors.New("no value is set")
+	}
+}
+
+// typ returns the type of the value set on v, or an empty string when none is.
+func (v valueResourceVariantModel) typ() string {
+	switch {
+	case !v.BooleanValue.IsNull():
+		return model.ValueTypeBoolean
+	case !v.StringValue.IsNull():
+		return model.ValueTypeString
+	case !v.JSONValue.IsNull():
+		return model.ValueTypeJSON
+	case !v.IntegerValue.IsNull():
+		return model.ValueTypeInteger
+	case !v.NumberValue.IsNull():
+		return model.ValueTypeNumber
+	default:
+		return ""
+	}
+}
+
+func variantState(e model.ValueEvaluation) valueResourceVariantModel {
+	var variant valueResourceVariantModel
+	if e.BooleanValue != nil {
+		variant.BooleanValue = types.BoolValue(e.BooleanValue.Value)
+	}
+	if e.StringValue != nil {
+		variant.StringValue = types.StringValue(e.StringValue.Value)
+	}
+	if e.JSONValue != nil {
+		b, _ := json.Marshal(e.JSONValue.Value)
+		variant.JSONValue = jsontypes.NewNormalizedValue(string(b))
+		for _, t := range e.JSONValue.Transforms {
+			variant.Transform = append(variant.Transform, valueResourceTransformModel{
+				Spec: types.StringValue(model.TFValueTransformSpec(t.Spec)),
+				Expr: types.StringValue(t.Expr),
 			})
 		}
-		if val.IntegerValue != nil {
-			if ints == nil {
-				ints = make([]valueResourceIntegerValueModel, 0, len(v.Variants))
-			}
-			var iv int64
-			var err error
-			iv, err = val.IntegerValue.Value.Int64()
-			if err != nil {
-				iv = 0
-			}
-			ints = append(ints, valueResourceIntegerValueModel{
-				Variant: types.StringValue(k),
-				Value:   types.Int64Value(iv),
-			})
+	}
+	if e.IntegerValue != nil {
+		iv, err := e.IntegerValue.Value.Int64()
+		if err != nil {
+			iv = 0
 		}
+		variant.IntegerValue = types.Int64Value(iv)
+	}
+	if e.NumberValue != nil {
+		// Parse at Terraform's own precision so that the value compares equal to the config.
+		nv, _, err := big.ParseFloat(string(e.NumberValue.Value), 10, 512, big.ToNearestEven)
+		if err != nil {
+			nv = new(big.Float)
+		}
+		variant.NumberValue = types.NumberValue(nv)
+	}
+	return variant
+}
+
+func valueState(v *model.Value) *valueResourceModel {
+	variants := make(map[string]valueResourceVariantModel, len(v.Variants))
+	for k, e := range v.Variants {
+		variants[k] = variantState(e)
 	}
 
 	targeting := make([]valueResourceTargetingModel, 0, len(v.Targeting.Rules))
@@ -379,7 +471,7 @@ func valueState(v *model.Value) *valueResourceModel {
 	for _, t := range v.Tests {
 		b, _ := json.Marshal(t.Variables)
 		tests = append(tests, valueResourceTestModel{
-			Variables: types.StringValue(string(b)),
+			Variables: jsontypes.NewNormalizedValue(string(b)),
 			Expected:  types.StringValue(t.Expected),
 		})
 	}
@@ -390,12 +482,39 @@ func valueState(v *model.Value) *valueResourceModel {
 		Description:    types.StringValue(v.Description),
 		Enabled:        types.BoolValue(v.Enabled),
 		DefaultVariant: types.StringValue(v.DefaultVariant),
-		BooleanValue:   bools,
-		StringValue:    strs,
-		JSONValue:      jsons,
-		IntegerValue:   ints,
+		Type:           valueTypeState(v),
+		Variants:       variants,
+		VariantValues:  types.DynamicNull(),
 		Targeting:      targeting,
 		Test:           tests,
+		ManagedFields:  types.SetNull(types.StringType),
+		CreateTime:     timeState(v.CreateTime),
+		UpdateTime:     timeState(v.UpdateTime),
+		Timeouts:       nullTimeouts(),
+	}
+}
+
+// timeState converts a unix time in seconds to an RFC3339 string, or null when unset.
+func timeState(n json.Number) types.String {
+	if n == "" {
+		return types.StringNull()
+	}
+	sec, err := n.Int64()
+	if err != nil {
+		return types.StringNull()
+	}
+	return types.StringValue(time.Unix(sec, 0).UTC().Format(time.RFC3339))
+}
+
+// nullTimeouts returns an unset timeouts block for states that are not built from a plan.
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{
+		Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		}),
 	}
 }
 
@@ -407,21 +526,42 @@ func (v *ValueResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	value, err := plan.value()
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	value, err := plan.value(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating value", "Invalid Attribute(s): "+err.Error())
 		return
 	}
 
+	if !plan.manages(managedFieldVariants) {
+		// There is no Value yet to take the variants from.
+		resp.Diagnostics.AddAttributeError(
+			path.Root("managed_fields"),
+			"Unmanaged Variants",
+			fmt.Sprintf("Value %s cannot be created without variants. Create it in the Edge UI and import it, or include %q in managed_fields.",
+				plan.ValueID.ValueString(), managedFieldVariants),
+		)
+		return
+	}
+
 	value, err = v.c.CreateValue(ctx, value)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating value", err.Error())
 		return
 	}
 
-	plan.ID = types.StringValue(value.ID)
-	diags = resp.State.Set(ctx, &plan)
+	state, diags := reconcileValueState(ctx, &plan, value)
+	resp.Diagnostics.Append(diags...)
+	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, value)...)
 }
 
 func (v *ValueResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -432,8 +572,30 @@ func (v *ValueResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	diags = resp.State.Set(ctx, &state)
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	value, err := v.c.GetValue(ctx, state.ID.ValueString())
+	if edgeclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Value not found, removing from state", map[string]any{"value_id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading value", err.Error())
+		return
+	}
+
+	newState, diags := reconcileValueState(ctx, &state, value)
+	resp.Diagnostics.Append(diags...)
+	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, value)...)
 }
 
 func (v *ValueResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -444,20 +606,56 @@ func (v *ValueResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	value, err := plan.value()
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	value, err := plan.value(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating value", "Invalid Attribute(s): "+err.Error())
 		return
 	}
 
-	_, err = v.c.UpdateValue(ctx, value)
+	revision, diags := getRevision(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The update is checked against the current Value, which the fields managed elsewhere are
+	// taken from, and conditioned on its revision. The Value stays locked against the targeting
+	// rules of this provider until it is written.
+	unlock := valueLocks.Lock(plan.ID.ValueString())
+	defer unlock()
+	var prior valueResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	revision, ok := currentRevision(ctx, v.c, &plan, &prior, value, revision, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	value, err = v.c.UpdateValue(ctx, value, revision)
+	if edgeclient.IsConflict(err) {
+		addConflictError(ctx, v.c, plan.ID.ValueString(), revision, err, &resp.Diagnostics)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating value", err.Error())
 		return
 	}
 
-	diags = resp.State.Set(ctx, &plan)
+	state, diags := reconcileValueState(ctx, &plan, value)
+	resp.Diagnostics.Append(diags...)
+	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, value)...)
 }
 
 func (v *ValueResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -468,6 +666,14 @@ func (v *ValueResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := v.c.DeleteValue(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting value", err.Error())
@@ -481,5 +687,5 @@ func (v *ValueResource) Configure(_ context.Context, req resource.ConfigureReque
 	if req.ProviderData == nil {
 		return
 	}
-	v.c = req.ProviderData.(*config)
+	v.c = req.ProviderData.(edgeclient.Client)
 }
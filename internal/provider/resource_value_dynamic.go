@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// expandVariantValues converts variant_values into variants, which then share the conversion to
+// and from the Edge API with the variants attribute. The framework does not allow dynamic
+// attributes inside collections, so variant_values is a single object keyed by variant name
+// rather than an attribute of each variant.
+//
+// Objects, maps, lists, tuples and sets become json variants, bools and strings boolean and
+// string variants. Numbers become integer variants when every number is a whole int64 and typ is
+// not number, and number variants otherwise, so that all numbers share one type. It reports
+// false when values is not fully known yet.
+func expandVariantValues(ctx context.Context, values types.Dynamic, typ string) (map[string]valueResourceVariantModel, bool, error) {
+	if values.IsUnknown() || values.IsUnderlyingValueUnknown() {
+		return nil, false, nil
+	}
+	if values.IsNull() || values.IsUnderlyingValueNull() {
+		return nil, true, nil
+	}
+	tfv, err := values.ToTerraformValue(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !tfv.IsFullyKnown() {
+		return nil, false, nil
+	}
+	if !tfv.Type().Is(tftypes.Object{}) && !tfv.Type().Is(tftypes.Map{}) {
+		return nil, true, fmt.Errorf("variant_values must be an object keyed by variant name, but got %s", tfv.Type())
+	}
+	var elems map[string]tftypes.Value
+	if err := tfv.As(&elems); err != nil {
+		return nil, true, err
+	}
+
+	whole := typ != model.ValueTypeNumber
+	for _, e := range elems {
+		if e.Type().Is(tftypes.Number) && !e.IsNull() {
+			var f big.Float
+			if err := e.As(&f); err != nil {
+				return nil, true, err
+			}
+			if _, acc := f.Int64(); !f.IsInt() || acc != big.Exact {
+				whole = false
+			}
+		}
+	}
+
+	variants := make(map[string]valueResourceVariantModel, len(elems))
+	for k, e := range elems {
+		var variant valueResourceVariantModel
+		switch {
+		case e.IsNull():
+			return nil, true, fmt.Errorf("variant %s has no value", k)
+		case e.Type().Is(tftypes.Bool):
+			var b bool
+			if err := e.As(&b); err != nil {
+				return nil, true, err
+			}
+			variant.BooleanValue = types.BoolValue(b)
+		case e.Type().Is(tftypes.String):
+			var s string
+			if err := e.As(&s); err != nil {
+				return nil, true, err
+			}
+			variant.StringValue = types.StringValue(s)
+		case e.Type().Is(tftypes.Number):
+			f := new(big.Float)
+			if err := e.As(&f); err != nil {
+				return nil, true, err
+			}
+			if whole {
+				i, _ := f.Int64()
+				variant.IntegerValue = types.Int64Value(i)
+			} else {
+				variant.NumberValue = types.NumberValue(f)
+			}
+		default:
+			doc, err := jsonDocument(e)
+			if err != nil {
+				return nil, true, fmt.Errorf("variant %s: %w", k, err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				return nil, true, fmt.Errorf("variant %s: %w", k, err)
+			}
+			variant.JSONValue = jsontypes.NewNormalizedValue(string(b))
+		}
+		variants[k] = variant
+	}
+	return variants, true, nil
+}
+
+// flattenVariantValues converts variants into variant_values. Transforms have no place in
+// variant_values and are left out.
+func flattenVariantValues(ctx context.Context, variants map[string]valueResourceVariantModel) (types.Dynamic, error) {
+	names := make([]string, 0, len(variants))
+	for k := range variants {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	attrTypes := make(map[string]tftypes.Type, len(variants))
+	vals := make(map[string]tftypes.Value, len(variants))
+	for _, k := range names {
+		v := variants[k]
+		var tfv tftypes.Value
+		switch v.typ() {
+		case model.ValueTypeBoolean:
+			tfv = tftypes.NewValue(tftypes.Bool, v.BooleanValue.ValueBool())
+		case model.ValueTypeString:
+			tfv = tftypes.NewValue(tftypes.String, v.StringValue.ValueString())
+		case model.ValueTypeInteger:
+			tfv = tftypes.NewValue(tftypes.Number, v.IntegerValue.ValueInt64())
+		case model.ValueTypeNumber:
+			tfv = tftypes.NewValue(tftypes.Number, v.NumberValue.ValueBigFloat())
+		case model.ValueTypeJSON:
+			doc, err := jsontypes.Decode(v.JSONValue.ValueString())
+			if err != nil {
+				return types.DynamicNull(), fmt.Errorf("variant %s: %w", k, err)
+			}
+			if tfv, err = terraformDocument(doc); err != nil {
+				return types.DynamicNull(), fmt.Errorf("variant %s: %w", k, err)
+			}
+		default:
+			return types.DynamicNull(), fmt.Errorf("variant %s has no value", k)
+		}
+		attrTypes[k] = tfv.Type()
+		vals[k] = tfv
+	}
+
+	obj := tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, vals)
+	v, err := types.DynamicType.ValueFromTerraform(ctx, obj)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	return v.(types.Dynamic), nil
+}
+
+// equivalentVariantValues reports whether a and b hold the same variants, comparing numbers by
+// value and collections by their elements regardless of their Terraform types.
+func equivalentVariantValues(ctx context.Context, a, b types.Dynamic) bool {
+	docA, errA := variantValuesDocument(ctx, a)
+	docB, errB := variantValuesDocument(ctx, b)
+	return errA == nil && errB == nil && reflect.DeepEqual(docA, docB)
+}
+
+func variantValuesDocument(ctx context.Context, v types.Dynamic) (any, error) {
+	tfv, err := v.ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return jsonDocument(tfv)
+}
+
+// jsonDocument converts v into the value encoding/json would decode its JSON representation
+// into, with numbers kept as json.Number.
+func jsonDocument(v tftypes.Value) (any, error) {
+	if !v.IsKnown() {
+		return nil, fmt.Errorf("value is not known")
+	}
+	if v.IsNull() {
+		return nil, nil
+	}
+	typ := v.Type()
+	switch {
+	case typ.Is(tftypes.Bool):
+		var b bool
+		err := v.As(&b)
+		return b, err
+	case typ.Is(tftypes.String):
+		var s string
+		err := v.As(&s)
+		return s, err
+	case typ.Is(tftypes.Number):
+		f := new(big.Float)
+		if err := v.As(&f); err != nil {
+			return nil, err
+		}
+		return json.Number(f.Text('g', -1)), nil
+	case typ.Is(tftypes.Object{}), typ.Is(tftypes.Map{}):
+		var elems map[string]tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		doc := make(map[string]any, len(elems))
+		for k, e := range elems {
+			d, err := jsonDocument(e)
+			if err != nil {
+				return nil, err
+			}
+			doc[k] = d
+		}
+		return doc, nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Tuple{}), typ.Is(tftypes.Set{}):
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		doc := make([]any, 0, len(elems))
+		for _, e := range elems {
+			d, err := jsonDocument(e)
+			if err != nil {
+				return nil, err
+			}
+			doc = append(doc, d)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", typ)
+	}
+}
+
+// terraformDocument converts a JSON document decoded by jsontypes.Decode into a Terraform value.
+// Objects become objects and arrays tuples, as HCL literals do.
+func terraformDocument(doc any) (tftypes.Value, error) {
+	switch d := doc.(type) {
+	case nil:
+		return tftypes.NewValue(tftypes.DynamicPseudoType, nil), nil
+	case bool:
+		return tftypes.NewValue(tftypes.Bool, d), nil
+	case string:
+		return tftypes.NewValue(tftypes.String, d), nil
+	case json.Number:
+		f, _, err := big.ParseFloat(string(d), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(tftypes.Number, f), nil
+	case map[string]any:
+		attrTypes := make(map[string]tftypes.Type, len(d))
+		vals := make(map[string]tftypes.Value, len(d))
+		for k, e := range d {
+			v, err := terraformDocument(e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attrTypes[k] = v.Type()
+			vals[k] = v
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, vals), nil
+	case []any:
+		elemTypes := make([]tftypes.Type, 0, len(d))
+		vals := make([]tftypes.Value, 0, len(d))
+		for _, e := range d {
+			v, err := terraformDocument(e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elemTypes = append(elemTypes, v.Type())
+			vals = append(vals, v)
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: elemTypes}, vals), nil
+	default:
+		return tftypes.Value{}, fmt.Errorf("unsupported JSON value %v", doc)
+	}
+}
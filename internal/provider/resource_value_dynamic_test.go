@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func dynamicObject(t *testing.T, vals map[string]tftypes.Value) types.Dynamic {
+	t.Helper()
+	attrTypes := make(map[string]tftypes.Type, len(vals))
+	for k, v := range vals {
+		attrTypes[k] = v.Type()
+	}
+	v, err := types.DynamicType.ValueFromTerraform(context.Background(), tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, vals))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.(types.Dynamic)
+}
+
+func TestExpandVariantValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	list := tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String}}
+	cfg := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"a": list}}
+	values := dynamicObject(t, map[string]tftypes.Value{
+		"on":   tftypes.NewValue(tftypes.Bool, true),
+		"name": tftypes.NewValue(tftypes.String, "x"),
+		"one":  tftypes.NewValue(tftypes.Number, 1),
+		"cfg": tftypes.NewValue(cfg, map[string]tftypes.Value{
+			"a": tftypes.NewValue(list, []tftypes.Value{
+				tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+				tftypes.NewValue(tftypes.String, "b"),
+			}),
+		}),
+	})
+
+	variants, known, err := expandVariantValues(ctx, values, "")
+	if err != nil || !known {
+		t.Fatalf("expected known variants, but got %v, %v", known, err)
+	}
+	want := map[string]string{
+		"on":   model.ValueTypeBoolean,
+		"name": model.ValueTypeString,
+		"one":  model.ValueTypeInteger,
+		"cfg":  model.ValueTypeJSON,
+	}
+	for k, typ := range want {
+		if got := variants[k].typ(); got != typ {
+			t.Errorf("expected variant %s to be a %s variant, but got %q", k, typ, got)
+		}
+	}
+	if got := variants["cfg"].JSONValue.ValueString(); got != `{"a":[1.5,"b"]}` {
+		t.Errorf("expected the object to be encoded as JSON, but got %s", got)
+	}
+	e, err := variants["cfg"].evaluation()
+	if err != nil || e.JSONValue == nil {
+		t.Fatalf("expected a json evaluation, but got %v, %v", e, err)
+	}
+
+	flattened, err := flattenVariantValues(ctx, variants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equivalentVariantValues(ctx, values, flattened) {
+		t.Fatalf("expected %s to round trip, but got %s", values, flattened)
+	}
+}
+
+func TestExpandVariantValues_Numbers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	values := dynamicObject(t, map[string]tftypes.Value{
+		"one":  tftypes.NewValue(tftypes.Number, 1),
+		"half": tftypes.NewValue(tftypes.Number, big.NewFloat(0.5)),
+	})
+	variants, _, err := expandVariantValues(ctx, values, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range variants {
+		if v.typ() != model.ValueTypeNumber {
+			t.Errorf("expected variant %s to be a number variant, but got %q", k, v.typ())
+		}
+	}
+
+	whole := dynamicObject(t, map[string]tftypes.Value{"one": tftypes.NewValue(tftypes.Number, 1)})
+	if variants, _, _ := expandVariantValues(ctx, whole, model.ValueTypeNumber); variants["one"].typ() != model.ValueTypeNumber {
+		t.Errorf("expected a number variant when the type is number, but got %q", variants["one"].typ())
+	}
+}
+
+func TestExpandVariantValues_Null(t *testing.T) {
+	t.Parallel()
+	values := dynamicObject(t, map[string]tftypes.Value{"off": tftypes.NewValue(tftypes.Bool, nil)})
+	if _, _, err := expandVariantValues(context.Background(), values, ""); err == nil {
+		t.Fatal("expected an error for a variant without value")
+	}
+}
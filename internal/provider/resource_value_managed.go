@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Fields of a Value that can be left to be managed elsewhere, such as in the Edge UI.
+const (
+	// managedFieldVariants covers variants and default_variant.
+	managedFieldVariants = "variants"
+	// managedFieldTargeting covers the targeting rules.
+	managedFieldTargeting = "targeting"
+)
+
+// managedFieldAttributes lists the attributes covered by each managed field.
+var managedFieldAttributes = map[string][]string{
+	managedFieldVariants:  {"variants", "variant_values", "default_variant"},
+	managedFieldTargeting: {"targeting"},
+}
+
+// manages reports whether v owns field. Every field is owned when managed_fields is not set.
+func (v *valueResourceModel) manages(field string) bool {
+	return managesField(v.ManagedFields, field)
+}
+
+// managesAll reports whether v owns every field of the Value.
+func (v *valueResourceModel) managesAll() bool {
+	return v.manages(managedFieldVariants) && v.manages(managedFieldTargeting)
+}
+
+func managesField(fields types.Set, field string) bool {
+	if fields.IsNull() || fields.IsUnknown() {
+		return true
+	}
+	for _, e := range fields.Elements() {
+		if e.Equal(types.StringValue(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeUnmanaged copies the fields v does not own from current, the Value as stored by the
+// Edge API, into value, so that an update leaves them as they are. The named targeting rules
+// are always kept.
+func (v *valueResourceModel) mergeUnmanaged(value, current *model.Value) {
+	if !v.manages(managedFieldVariants) {
+		value.Variants = current.Variants
+		value.DefaultVariant = current.DefaultVariant
+	}
+	if !v.manages(managedFieldTargeting) {
+		value.Targeting = current.Targeting
+	} else {
+		value.Targeting.Rules = mergeNamedRules(value.Targeting.Rules, current.Targeting.Rules)
+	}
+}
+
+// withoutNamedRules returns v without its named targeting rules. Those are managed by
+// edge_value_targeting_rule, so the value resources neither track nor remove them.
+func withoutNamedRules(v *model.Value) *model.Value {
+	out := *v
+	out.Targeting.Rules = nil
+	for _, r := range v.Targeting.Rules {
+		if r.Name == "" {
+			out.Targeting.Rules = append(out.Targeting.Rules, r)
+		}
+	}
+	return &out
+}
+
+// mergeNamedRules returns rules, the unnamed rules to write, with the named rules of current put
+// back where they are. Each named rule keeps its place among the unnamed rules of current, which
+// rules replace in order.
+func mergeNamedRules(rules, current []model.ValueTargetingRule) []model.ValueTargetingRule {
+	merged := make([]model.ValueTargetingRule, 0, len(rules)+len(current))
+	i := 0
+	for _, r := range current {
+		switch {
+		case r.Name != "":
+			merged = append(merged, r)
+		case i < len(rules):
+			merged = append(merged, rules[i])
+			i++
+		}
+	}
+	return append(merged, rules[i:]...)
+}
+
+// changedOwnedFields returns the fields owned by v that current, the Value as stored by the Edge
+// API, changed since prior, the state the provider last wrote or read.
+func (v *valueResourceModel) changedOwnedFields(ctx context.Context, prior *valueResourceModel, current *model.Value) ([]string, error) {
+	seen, diags := reconcileValueState(ctx, prior, current)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unable to read the current value")
+	}
+	before, err := prior.value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	after, err := seen.value(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// enabled, description and tests are always owned by the resource.
+	var changed []string
+	if before.Enabled != after.Enabled {
+		changed = append(changed, "enabled")
+	}
+	if before.Description != after.Description {
+		changed = append(changed, "description")
+	}
+	if (len(before.Tests) > 0 || len(after.Tests) > 0) && !reflect.DeepEqual(before.Tests, after.Tests) {
+		changed = append(changed, "tests")
+	}
+	if v.manages(managedFieldVariants) &&
+		(before.DefaultVariant != after.DefaultVariant || !reflect.DeepEqual(before.Variants, after.Variants)) {
+		changed = append(changed, managedFieldVariants)
+	}
+	if v.manages(managedFieldTargeting) && !reflect.DeepEqual(before.Targeting.Rules, after.Targeting.Rules) {
+		changed = append(changed, managedFieldTargeting)
+	}
+	return changed, nil
+}
+
+// currentRevision reads the Value that value is about to replace and returns the revision to
+// condition the update on. The fields plan does not own and the unknown fields are taken from
+// it. The update is rejected with a conflict when a field plan owns changed since prior, the
+// state the provider last wrote or read. The caller keeps the Value locked with valueLocks
+// until the update is written.
+//
+// The Edge API has no etag: the update time is the only revision of a Value, and with its
+// one-second resolution the If-Match precondition misses a change made in the same second as
+// the last read. The owned fields are compared for that reason, even when the revisions match.
+func currentRevision(ctx context.Context, c edgeclient.Client, plan, prior *valueResourceModel, value *model.Value, revision string, diags *diag.Diagnostics) (string, bool) {
+	current, err := c.GetValue(ctx, plan.ID.ValueString())
+	if err != nil {
+		diags.AddError("Error updating value", err.Error())
+		return "", false
+	}
+	changed, err := plan.changedOwnedFields(ctx, prior, current)
+	if err != nil {
+		diags.AddError("Error updating value", err.Error())
+		return "", false
+	}
+	if len(changed) > 0 {
+		err := fmt.Errorf("%w: %s changed", edgeclient.ErrConflict, strings.Join(changed, " and "))
+		addConflictError(ctx, c, plan.ID.ValueString(), revision, err, diags)
+		return "", false
+	}
+	plan.mergeUnmanaged(value, current)
+	value.KeepUnknownFields(current)
+	return string(current.UpdateTime), true
+}
+
+// validateManagedFields checks that the attributes of owned fields are set, and that the
+// attributes of fields managed elsewhere are not. It reports false when managed_fields is not
+// known yet.
+func validateManagedFields(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) (types.Set, bool) {
+	var fields types.Set
+	if d := config.GetAttribute(ctx, path.Root("managed_fields"), &fields); d.HasError() || fields.IsUnknown() {
+		return fields, false
+	}
+
+	// Owned variants are declared by either variants or variant_values.
+	var variantValues attr.Value
+	optional := map[string]bool{"targeting": true, "variant_values": true}
+	if d := config.GetAttribute(ctx, path.Root("variant_values"), &variantValues); d.HasError() || !variantValues.IsNull() {
+		optional["variants"] = true
+	}
+
+	for _, field := range []string{managedFieldVariants, managedFieldTargeting} {
+		managed := managesField(fields, field)
+		for _, name := range managedFieldAttributes[field] {
+			var v attr.Value
+			if d := config.GetAttribute(ctx, path.Root(name), &v); d.HasError() || v.IsUnknown() {
+				continue
+			}
+			set := !v.IsNull()
+			if l, ok := v.(types.List); ok {
+				set = len(l.Elements()) > 0
+			}
+			switch {
+			case managed && !set && !optional[name]:
+				diags.AddAttributeError(
+					path.Root(name),
+					"Missing Required Attribute",
+					fmt.Sprintf("%s is required while managed_fields includes %q.", name, field),
+				)
+			case !managed && set:
+				diags.AddAttributeError(
+					path.Root(name),
+					"Unmanaged Attribute",
+					fmt.Sprintf("%s is managed outside of Terraform because managed_fields does not include %q, so it must not be set.", name, field),
+				)
+			}
+		}
+	}
+	return fields, true
+}
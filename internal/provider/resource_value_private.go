@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// privateKeyRevision is the private state key holding the revision of a Value as last read or
+// written by the provider. Update reports it as the time the Value was last read when the Value
+// changed since.
+const privateKeyRevision = "revision"
+
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+type privateRevision struct {
+	UpdateTime json.Number `json:"updateTime"`
+}
+
+// getRevision returns the revision kept in private, or an empty string when there is none.
+func getRevision(ctx context.Context, private privateStateGetter) (string, diag.Diagnostics) {
+	b, diags := private.GetKey(ctx, privateKeyRevision)
+	if diags.HasError() || len(b) == 0 {
+		return "", diags
+	}
+	var rev privateRevision
+	if err := json.Unmarshal(b, &rev); err != nil {
+		diags.AddError("Invalid Private State", "Unable to decode the revision of the value: "+err.Error())
+		return "", diags
+	}
+	return string(rev.UpdateTime), diags
+}
+
+// setPrivate keeps the revision of v in private. It removes the revision when the Edge API did
+// not report one.
+func setPrivate(ctx context.Context, private privateStateSetter, v *model.Value) diag.Diagnostics {
+	if v.UpdateTime == "" {
+		return private.SetKey(ctx, privateKeyRevision, nil)
+	}
+	b, err := json.Marshal(privateRevision{UpdateTime: v.UpdateTime})
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Invalid Private State", "Unable to encode the revision of the value: "+err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, privateKeyRevision, b)
+}
+
+// addConflictError reports an update of the Value id that was rejected because the Value changed
+// after revision. The current Value is read to tell when it changed, and who changed it is taken
+// from err when the Edge API reports it.
+func addConflictError(ctx context.Context, c edgeclient.Client, id, revision string, err error, diags *diag.Diagnostics) {
+	seen := timeState(json.Number(revision)).ValueString()
+	detail := fmt.Sprintf("Value %s was changed outside of Terraform after it was last read at %s.", id, seen)
+	if current, gerr := c.GetValue(ctx, id); gerr == nil && current.UpdateTime != "" {
+		detail = fmt.Sprintf("Value %s was changed outside of Terraform at %s, after it was last read at %s.",
+			id, timeState(current.UpdateTime).ValueString(), seen)
+	}
+	var e *edgeclient.Error
+	if errors.As(err, &e) && e.Actor != "" {
+		detail += fmt.Sprintf(" It was changed by %s.", e.Actor)
+	} else {
+		detail += " The Edge API did not report who changed it."
+	}
+	diags.AddError(
+		"Value Changed Concurrently",
+		detail+" The update was not applied so that the other change is kept. "+
+			"Refresh and review the plan before applying again.\n\n"+err.Error(),
+	)
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// reconcileValueState returns the state of the Value v returned by the Edge API. Wherever v is
+// semantically equal to prior, the plan or state it was written from, the value of prior is
+// kept, so that formatting, omitted defaults and number precision do not show up as a change.
+// Anything the Edge API really changed is taken from v, except for the named targeting rules.
+func reconcileValueState(ctx context.Context, prior *valueResourceModel, v *model.Value) (*valueResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	state := valueState(withoutNamedRules(v))
+	state.ManagedFields = prior.ManagedFields
+	state.Timeouts = prior.Timeouts
+
+	if prior.Description.IsNull() && v.Description == "" {
+		state.Description = types.StringNull()
+	}
+	if state.Type.IsNull() && !prior.Type.IsUnknown() {
+		state.Type = prior.Type
+	}
+
+	for k, variant := range state.Variants {
+		if p, ok := prior.Variants[k]; ok {
+			state.Variants[k] = reconcileVariant(p, variant)
+		}
+	}
+
+	if len(prior.Targeting) == len(state.Targeting) {
+		for i, t := range state.Targeting {
+			p := prior.Targeting[i]
+			if p.Variant.Equal(t.Variant) && p.Expr.Equal(t.Expr) &&
+				model.ValueTargetingRuleSpecFrom(p.Spec.ValueString()) == model.ValueTargetingRuleSpecFrom(t.Spec.ValueString()) {
+				state.Targeting[i] = p
+			}
+		}
+		if len(state.Targeting) == 0 {
+			state.Targeting = prior.Targeting
+		}
+	}
+
+	if len(prior.Test) == len(state.Test) {
+		for i, t := range state.Test {
+			p := prior.Test[i]
+			if p.Expected.Equal(t.Expected) && jsontypes.Equivalent(p.Variables.ValueString(), t.Variables.ValueString()) {
+				state.Test[i] = p
+			}
+		}
+		if len(state.Test) == 0 {
+			state.Test = prior.Test
+		}
+	}
+
+	// Variants declared through variant_values are read back into it.
+	if !prior.VariantValues.IsNull() {
+		values, err := flattenVariantValues(ctx, state.Variants)
+		if err != nil {
+			diags.AddError("Error reading variant values", err.Error())
+			return nil, diags
+		}
+		if equivalentVariantValues(ctx, prior.VariantValues, values) {
+			values = prior.VariantValues
+		}
+		state.Variants = nil
+		state.VariantValues = values
+	}
+
+	// Fields managed elsewhere are not tracked, so that changing them never shows up as a diff.
+	if !prior.manages(managedFieldVariants) {
+		state.Variants = prior.Variants
+		state.VariantValues = prior.VariantValues
+		state.DefaultVariant = prior.DefaultVariant
+	}
+	if !prior.manages(managedFieldTargeting) {
+		state.Targeting = prior.Targeting
+	}
+	return state, diags
+}
+
+// reconcileVariant returns variant, keeping the values of prior that are semantically equal.
+func reconcileVariant(prior, variant valueResourceVariantModel) valueResourceVariantModel {
+	if prior.typ() != variant.typ() {
+		return variant
+	}
+	switch variant.typ() {
+	case model.ValueTypeJSON:
+		if jsontypes.Equivalent(prior.JSONValue.ValueString(), variant.JSONValue.ValueString()) {
+			variant.JSONValue = prior.JSONValue
+		}
+		if equalTransforms(prior.Transform, variant.Transform) {
+			variant.Transform = prior.Transform
+		}
+	case model.ValueTypeNumber:
+		if prior.NumberValue.ValueBigFloat().Cmp(variant.NumberValue.ValueBigFloat()) == 0 {
+			variant.NumberValue = prior.NumberValue
+		}
+	}
+	return variant
+}
+
+// equalTransforms reports whether a and b hold the same expressions with equivalent specs.
+func equalTransforms(a, b []valueResourceTransformModel) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Expr.Equal(b[i].Expr) ||
+			model.ValueTransformSpecFrom(a[i].Spec.ValueString()) != model.ValueTransformSpecFrom(b[i].Spec.ValueString()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReconcileValueState(t *testing.T) {
+	t.Parallel()
+	precise, _, _ := big.ParseFloat("0.1", 10, 512, big.ToNearestEven)
+	plan := &valueResourceModel{
+		ID:             types.StringUnknown(),
+		ValueID:        types.StringValue("test"),
+		Description:    types.StringNull(),
+		Enabled:        types.BoolValue(true),
+		DefaultVariant: types.StringValue("json"),
+		Type:           types.StringValue(model.ValueTypeJSON),
+		Variants: map[string]valueResourceVariantModel{
+			"json": {
+				JSONValue: jsontypes.NewNormalizedValue("{\n  \"b\": 2,\n  \"a\": 1\n}"),
+				Transform: []valueResourceTransformModel{
+					{Spec: types.StringNull(), Expr: types.StringValue("{}")},
+				},
+			},
+			"list": {JSONValue: jsontypes.NewNormalizedValue(`[1, 2]`)},
+		},
+		Targeting: []valueResourceTargetingModel{
+			{Variant: types.StringValue("json"), Spec: types.StringNull(), Expr: types.StringValue("env == 'dev'")},
+		},
+		Test: []valueResourceTestModel{
+			{Variables: jsontypes.NewNormalizedValue(`{ "env": "dev" }`), Expected: types.StringValue("json")},
+		},
+		Timeouts: nullTimeouts(),
+	}
+	value := &model.Value{
+		ID:             "test",
+		Enabled:        true,
+		DefaultVariant: "json",
+		Variants: model.ValueVariants{
+			"json": {JSONValue: &model.ValueJSONValue{
+				Value:      map[string]any{"a": 1, "b": 2},
+				Transforms: []*model.ValueTransform{{Spec: model.ValueTransformSpecCEL, Expr: "{}"}},
+			}},
+			"list": {JSONValue: &model.ValueJSONValue{Value: []any{2, 1}}},
+		},
+		Targeting: model.ValueTargeting{Rules: []model.ValueTargetingRule{
+			{Variant: "json", Spec: model.ValueTargetingRuleSpecCEL, Expr: "env == 'dev'"},
+		}},
+		Tests:      []*model.EvaluationTest{{Variables: map[string]any{"env": "dev"}, Expected: "json"}},
+		UpdateTime: "1682089734",
+	}
+
+	got, diags := reconcileValueState(context.Background(), plan, value)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got.ID.ValueString() != "test" || !got.Description.IsNull() {
+		t.Fatalf("expected the ID from the Edge API and a null description, but got %s and %s", got.ID, got.Description)
+	}
+	if got.UpdateTime.ValueString() != "2023-04-21T15:08:54Z" {
+		t.Fatalf("expected the update time from the Edge API, but got %s", got.UpdateTime)
+	}
+	if !reflect.DeepEqual(got.Variants["json"], plan.Variants["json"]) {
+		t.Fatalf("expected the planned json variant to be kept, but got %v", got.Variants["json"])
+	}
+	if want := jsontypes.NewNormalizedValue(`[2,1]`); !got.Variants["list"].JSONValue.Equal(want) {
+		t.Fatalf("expected the reordered list from the Edge API, but got %s", got.Variants["list"].JSONValue)
+	}
+	if !reflect.DeepEqual(got.Targeting, plan.Targeting) || !reflect.DeepEqual(got.Test, plan.Test) {
+		t.Fatalf("expected the planned targeting and tests to be kept, but got %v and %v", got.Targeting, got.Test)
+	}
+
+	plan.Variants = map[string]valueResourceVariantModel{"rate": {NumberValue: types.NumberValue(precise)}}
+	value.Variants = model.ValueVariants{"rate": {NumberValue: &model.ValueNumberValue{Value: "0.1"}}}
+	if got, _ := reconcileValueState(context.Background(), plan, value); got.Variants["rate"].NumberValue.ValueBigFloat() != precise {
+		t.Fatalf("expected the planned number to be kept, but got %s", got.Variants["rate"].NumberValue)
+	}
+}
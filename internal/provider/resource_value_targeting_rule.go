@@ -0,0 +1,473 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &ValueTargetingRuleResource{}
+	_ resource.ResourceWithImportState    = &ValueTargetingRuleResource{}
+	_ resource.ResourceWithValidateConfig = &ValueTargetingRuleResource{}
+)
+
+// targetingRuleMaxAttempts caps how often a targeting rule change is applied to a Value that keeps
+// changing concurrently.
+const targetingRuleMaxAttempts = 5
+
+func NewValueTargetingRuleResource() resource.Resource {
+	return &ValueTargetingRuleResource{}
+}
+
+// ValueTargetingRuleResource manages a single named rule among the targeting rules of a Value
+// owned elsewhere. Every write reads the Value, changes only this rule and writes it back on
+// condition that the Value did not change in between.
+type ValueTargetingRuleResource struct {
+	c edgeclient.Client
+}
+
+type valueTargetingRuleResourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	ValueID  types.String   `tfsdk:"value_id"`
+	Name     types.String   `tfsdk:"name"`
+	Variant  types.String   `tfsdk:"variant"`
+	Spec     types.String   `tfsdk:"spec"`
+	Expr     types.String   `tfsdk:"expr"`
+	Priority types.Int64    `tfsdk:"priority"`
+	Before   types.String   `tfsdk:"before"`
+	After    types.String   `tfsdk:"after"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *ValueTargetingRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	i := strings.LastIndex(req.ID, "/")
+	if i <= 0 || i == len(req.ID)-1 {
+		resp.Diagnostics.AddError(
+			"Error importing targeting rule",
+			fmt.Sprintf("expected an ID of the form <value_id>/<name>, but got %q", req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value_id"), req.ID[:i])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID[i+1:])...)
+}
+
+func (r *ValueTargetingRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_value_targeting_rule"
+}
+
+func (r *ValueTargetingRuleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	placement := []string{"priority", "before", "after"}
+	conflicting := func(name string) []path.Expression {
+		var exprs []path.Expression
+		for _, p := range placement {
+			if p != name {
+				exprs = append(exprs, path.MatchRoot(p))
+			}
+		}
+		return exprs
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A named targeting rule of an existing Edge value, managed apart from the value itself. " +
+			"The `edge_value` or typed value resource owning the value keeps the rule on its applies, without listing it in its `targeting`. " +
+			"The placement of the rule is applied when it is created or when the placement changes: rules inserted later may move it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Computed ID of the form `<value_id>/<name>`.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"value_id": schema.StringAttribute{
+				Description: "The ID of the Value the rule belongs to.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the rule, unique within the Value.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^/]+$`), "must not be empty or contain /"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"variant": schema.StringAttribute{
+				Description: "The variant served when the rule matches. It must be declared by the Value.",
+				Required:    true,
+			},
+			"spec": schema.StringAttribute{
+				Optional: true,
+			},
+			"expr": schema.StringAttribute{
+				Required: true,
+			},
+			"priority": schema.Int64Attribute{
+				Description: "The position to insert the rule at among the rules of the Value, starting at 0 for the rule evaluated first. A position past the last rule appends the rule. The rule is appended when no placement is set.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.ConflictsWith(conflicting("priority")...),
+				},
+			},
+			"before": schema.StringAttribute{
+				Description: "The name of the rule to insert the rule before.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(conflicting("before")...),
+				},
+			},
+			"after": schema.StringAttribute{
+				Description: "The name of the rule to insert the rule after.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(conflicting("after")...),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *ValueTargetingRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config valueTargetingRuleResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateTargetingExpr(path.Root("expr"), config.Spec, config.Expr, &resp.Diagnostics)
+
+	if config.Name.IsUnknown() {
+		return
+	}
+	for _, p := range []struct {
+		name  string
+		value types.String
+	}{{"before", config.Before}, {"after", config.After}} {
+		if p.value.Equal(config.Name) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(p.name),
+				"Invalid Rule Placement",
+				fmt.Sprintf("The rule cannot be placed %s itself.", p.name),
+			)
+		}
+	}
+}
+
+// rule returns the targeting rule described by v.
+func (v *valueTargetingRuleResourceModel) rule() model.ValueTargetingRule {
+	return model.ValueTargetingRule{
+		Name:    v.Name.ValueString(),
+		Variant: v.Variant.ValueString(),
+		Spec:    model.ValueTargetingRuleSpecFrom(v.Spec.ValueString()),
+		Expr:    v.Expr.ValueString(),
+	}
+}
+
+// samePlacement reports whether v and other place the rule the same way.
+func (v *valueTargetingRuleResourceModel) samePlacement(other *valueTargetingRuleResourceModel) bool {
+	return v.Priority.Equal(other.Priority) && v.Before.Equal(other.Before) && v.After.Equal(other.After)
+}
+
+// insert places rule among rules as v describes, and returns the resulting rules.
+func (v *valueTargetingRuleResourceModel) insert(rules []model.ValueTargetingRule, rule model.ValueTargetingRule) ([]model.ValueTargetingRule, error) {
+	at := len(rules)
+	switch {
+	case !v.Priority.IsNull():
+		if p := int(v.Priority.ValueInt64()); p < at {
+			at = p
+		}
+	case !v.Before.IsNull():
+		i := findRule(rules, v.Before.ValueString())
+		if i < 0 {
+			return nil, fmt.Errorf("there is no targeting rule named %q to insert the rule before", v.Before.ValueString())
+		}
+		at = i
+	case !v.After.IsNull():
+		i := findRule(rules, v.After.ValueString())
+		if i < 0 {
+			return nil, fmt.Errorf("there is no targeting rule named %q to insert the rule after", v.After.ValueString())
+		}
+		at = i + 1
+	}
+
+	out := make([]model.ValueTargetingRule, 0, len(rules)+1)
+	out = append(out, rules[:at]...)
+	out = append(out, rule)
+	return append(out, rules[at:]...), nil
+}
+
+// findRule returns the index of the rule called name, or -1 if there is none.
+func findRule(rules []model.ValueTargetingRule, name string) int {
+	for i, rule := range rules {
+		if rule.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkVariant reports an error if value does not declare the variant of rule.
+func checkVariant(value *model.Value, rule model.ValueTargetingRule) error {
+	if _, ok := value.Variants[rule.Variant]; !ok {
+		return fmt.Errorf("value %s does not declare variant %q", value.ID, rule.Variant)
+	}
+	return nil
+}
+
+// state returns the state of rule as read from the Edge API. The spec of v is kept when it is
+// equivalent, and the placement, which is not read back, is always kept.
+func (v *valueTargetingRuleResourceModel) state(rule model.ValueTargetingRule) *valueTargetingRuleResourceModel {
+	state := *v
+	state.ID = types.StringValue(v.ValueID.ValueString() + "/" + rule.Name)
+	state.Variant = types.StringValue(rule.Variant)
+	state.Expr = types.StringValue(rule.Expr)
+	if model.ValueTargetingRuleSpecFrom(v.Spec.ValueString()) != rule.Spec {
+		state.Spec = types.StringValue(model.TFValueTargetingRuleSpec(rule.Spec))
+	}
+	if state.Timeouts.IsNull() {
+		state.Timeouts = nullTimeouts()
+	}
+	return &state
+}
+
+// errRuleUnchanged is returned by a modification that leaves the Value as it is.
+var errRuleUnchanged = errors.New("targeting rule unchanged")
+
+// modifyTargeting reads the Value id, applies modify to it and writes it back on condition that
+// it did not change in between. The Value stays locked in valueLocks meanwhile, since rules of
+// the same Value written by this provider within one second share a revision. A change by
+// someone else is applied again to the new Value, as it only touches a single rule, up to
+// targetingRuleMaxAttempts times. modify returns errRuleUnchanged to skip the write, in which
+// case the Value as read is returned.
+func (r *ValueTargetingRuleResource) modifyTargeting(ctx context.Context, id string, modify func(*model.Value) error) (*model.Value, error) {
+	unlock := valueLocks.Lock(id)
+	defer unlock()
+
+	var revision string
+	var lastErr error
+	for attempt := 0; attempt < targetingRuleMaxAttempts; attempt++ {
+		value, err := r.c.GetValue(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if lastErr != nil && string(value.UpdateTime) == revision {
+			// The Value did not change, so the update was rejected for another reason.
+			return nil, lastErr
+		}
+		if err := modify(value); errors.Is(err, errRuleUnchanged) {
+			return value, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		revision = string(value.UpdateTime)
+		updated, err := r.c.UpdateValue(ctx, value, revision)
+		if !edgeclient.IsConflict(err) {
+			return updated, err
+		}
+		tflog.Debug(ctx, "Value changed concurrently, applying the targeting rule again", map[string]any{"value_id": id})
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (r *ValueTargetingRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan valueTargetingRuleResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	rule := plan.rule()
+	_, err := r.modifyTargeting(ctx, plan.ValueID.ValueString(), func(value *model.Value) error {
+		if findRule(value.Targeting.Rules, rule.Name) >= 0 {
+			return fmt.Errorf("value %s already has a targeting rule named %q; import it to manage it with Terraform", value.ID, rule.Name)
+		}
+		if err := checkVariant(value, rule); err != nil {
+			return err
+		}
+		rules, err := plan.insert(value.Targeting.Rules, rule)
+		if err != nil {
+			return err
+		}
+		value.Targeting.Rules = rules
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating targeting rule", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan.state(rule))
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ValueTargetingRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state valueTargetingRuleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	value, err := r.c.GetValue(ctx, state.ValueID.ValueString())
+	if edgeclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Value not found, removing targeting rule from state", map[string]any{"value_id": state.ValueID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading targeting rule", err.Error())
+		return
+	}
+
+	i := findRule(value.Targeting.Rules, state.Name.ValueString())
+	if i < 0 {
+		tflog.Warn(ctx, "Targeting rule not found, removing from state", map[string]any{
+			"value_id": state.ValueID.ValueString(),
+			"name":     state.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state.state(value.Targeting.Rules[i]))
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ValueTargetingRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state valueTargetingRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	rule := plan.rule()
+	_, err := r.modifyTargeting(ctx, plan.ValueID.ValueString(), func(value *model.Value) error {
+		i := findRule(value.Targeting.Rules, rule.Name)
+		if i < 0 {
+			return fmt.Errorf("value %s no longer has a targeting rule named %q", value.ID, rule.Name)
+		}
+		if err := checkVariant(value, rule); err != nil {
+			return err
+		}
+		rule.UnknownFields = value.Targeting.Rules[i].UnknownFields
+		if plan.samePlacement(&state) {
+			value.Targeting.Rules[i] = rule
+			return nil
+		}
+		rest := append(value.Targeting.Rules[:i:i], value.Targeting.Rules[i+1:]...)
+		rules, err := plan.insert(rest, rule)
+		if err != nil {
+			return err
+		}
+		value.Targeting.Rules = rules
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating targeting rule", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan.state(rule))
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ValueTargetingRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state valueTargetingRuleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultValueTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	_, err := r.modifyTargeting(ctx, state.ValueID.ValueString(), func(value *model.Value) error {
+		i := findRule(value.Targeting.Rules, state.Name.ValueString())
+		if i < 0 {
+			return errRuleUnchanged
+		}
+		value.Targeting.Rules = append(value.Targeting.Rules[:i:i], value.Targeting.Rules[i+1:]...)
+		return nil
+	})
+	// The rule is gone with its Value.
+	if err != nil && !edgeclient.IsNotFound(err) {
+		resp.Diagnostics.AddError("Error deleting targeting rule", err.Error())
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *ValueTargetingRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.c = req.ProviderData.(edgeclient.Client)
+}
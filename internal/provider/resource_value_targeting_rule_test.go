@@ -0,0 +1,438 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/jarcoal/httpmock"
+)
+
+// targetingRuleMock serves a single Value whose updates are conditioned on its update time.
+type targetingRuleMock struct {
+	mu    sync.Mutex
+	value model.Value
+	// concurrent, when set, is applied to the Value right before the next update is checked,
+	// as if someone else had written it in between.
+	concurrent func(*model.Value)
+	// rejected, when set, makes every update fail its precondition without a change of the Value.
+	rejected bool
+	// readDelay delays the responses to reads, so that concurrent writers read the same Value.
+	readDelay time.Duration
+	// updates counts the update requests.
+	updates int
+}
+
+func newTargetingRuleMock(t *testing.T, data string) (*targetingRuleMock, edgeclient.Client) {
+	m := &targetingRuleMock{}
+	if err := json.Unmarshal([]byte(data), &m.value); err != nil {
+		t.Fatal(err)
+	}
+	m.value.UpdateTime = "1682089734"
+
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		func(req *http.Request) (*http.Response, error) {
+			m.mu.Lock()
+			resp, err := httpmock.NewJsonResponse(200, &m.value)
+			delay := m.readDelay
+			m.mu.Unlock()
+			time.Sleep(delay)
+			return resp, err
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		func(req *http.Request) (*http.Response, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			var in model.Value
+			if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+				return nil, err
+			}
+			m.updates++
+			if m.rejected {
+				return httpmock.NewStringResponse(412, `{"code":"failed_precondition","message":"value is locked"}`), nil
+			}
+			if m.concurrent != nil {
+				m.concurrent(&m.value)
+				m.concurrent = nil
+				m.value.UpdateTime = "1682089800"
+			}
+			if req.Header.Get("If-Match") != `"`+string(m.value.UpdateTime)+`"` {
+				return httpmock.NewStringResponse(412, `{"code":"failed_precondition","message":"value was updated"}`), nil
+			}
+			in.UpdateTime = "1682089900"
+			m.value = in
+			return httpmock.NewJsonResponse(200, &m.value)
+		},
+	)
+
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		func(req *http.Request) (*http.Response, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			var in model.Value
+			if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+				return nil, err
+			}
+			in.UpdateTime = "1682089734"
+			m.value = in
+			return httpmock.NewJsonResponse(200, &m.value)
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		func(req *http.Request) (*http.Response, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return httpmock.NewJsonResponse(200, &m.value)
+		},
+	)
+
+	return m, edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+}
+
+// ruleNames returns the names of the targeting rules of the Value, with "" for unnamed rules.
+func (m *targetingRuleMock) ruleNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.value.Targeting.Rules))
+	for _, rule := range m.value.Targeting.Rules {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+func (m *targetingRuleMock) checkRuleNames(want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if got := m.ruleNames(); !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("expected targeting rules %q, but got %q", want, got)
+		}
+		return nil
+	}
+}
+
+func TestAccResourceEdgeValueTargetingRule(t *testing.T) {
+	m, client := newTargetingRuleMock(t, booleanTestdata)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceTargetingRules("tenant == 'b'"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value_targeting_rule.a", "id", "test-bool-value/tenant-a"),
+					resource.TestCheckResourceAttr("edge_value_targeting_rule.a", "variant", "on"),
+					resource.TestCheckNoResourceAttr("edge_value_targeting_rule.a", "spec"),
+					resource.TestCheckResourceAttr("edge_value_targeting_rule.b", "after", "tenant-a"),
+					m.checkRuleNames("tenant-a", "tenant-b", "", ""),
+				),
+			},
+			{
+				Config: providerConfig + testAccResourceTargetingRules("tenant == 'c'"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value_targeting_rule.b", "expr", "tenant == 'c'"),
+					m.checkRuleNames("tenant-a", "tenant-b", "", ""),
+				),
+			},
+			{
+				ResourceName:            "edge_value_targeting_rule.b",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"after"},
+			},
+		},
+		CheckDestroy: m.checkRuleNames("", ""),
+	})
+}
+
+func TestAccResourceEdgeValueTargetingRule_UndeclaredVariant(t *testing.T) {
+	_, client := newTargetingRuleMock(t, booleanTestdata)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + strings.Replace(testAccResourceTargetingRules("tenant == 'b'"), `variant = "on"`, `variant = "maybe"`, 1),
+				ExpectError: regexp.MustCompile(`does not declare variant "maybe"`),
+			},
+		},
+	})
+}
+
+func TestMergeNamedRules(t *testing.T) {
+	t.Parallel()
+	rule := func(name, expr string) model.ValueTargetingRule {
+		return model.ValueTargetingRule{Name: name, Expr: expr}
+	}
+	current := []model.ValueTargetingRule{rule("a", "a"), rule("", "x"), rule("b", "b"), rule("", "y")}
+	tests := map[string]struct {
+		rules []model.ValueTargetingRule
+		want  []model.ValueTargetingRule
+	}{
+		"same":    {rules: []model.ValueTargetingRule{rule("", "x"), rule("", "y")}, want: current},
+		"changed": {rules: []model.ValueTargetingRule{rule("", "z"), rule("", "y")}, want: []model.ValueTargetingRule{rule("a", "a"), rule("", "z"), rule("b", "b"), rule("", "y")}},
+		"fewer":   {rules: []model.ValueTargetingRule{rule("", "x")}, want: []model.ValueTargetingRule{rule("a", "a"), rule("", "x"), rule("b", "b")}},
+		"more":    {rules: []model.ValueTargetingRule{rule("", "x"), rule("", "y"), rule("", "z")}, want: append(current, rule("", "z"))},
+		"none":    {want: []model.ValueTargetingRule{rule("a", "a"), rule("b", "b")}},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := mergeNamedRules(tt.rules, current); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValueTargetingRuleInsert(t *testing.T) {
+	t.Parallel()
+	rules := []model.ValueTargetingRule{{Name: "a"}, {Name: "b"}}
+	tests := map[string]struct {
+		plan valueTargetingRuleResourceModel
+		want []string
+		err  bool
+	}{
+		"append":          {plan: valueTargetingRuleResourceModel{}, want: []string{"a", "b", "new"}},
+		"priority":        {plan: valueTargetingRuleResourceModel{Priority: types.Int64Value(0)}, want: []string{"new", "a", "b"}},
+		"priority past":   {plan: valueTargetingRuleResourceModel{Priority: types.Int64Value(5)}, want: []string{"a", "b", "new"}},
+		"before":          {plan: valueTargetingRuleResourceModel{Before: types.StringValue("b")}, want: []string{"a", "new", "b"}},
+		"after":           {plan: valueTargetingRuleResourceModel{After: types.StringValue("b")}, want: []string{"a", "b", "new"}},
+		"unknown before":  {plan: valueTargetingRuleResourceModel{Before: types.StringValue("c")}, err: true},
+		"unknown after":   {plan: valueTargetingRuleResourceModel{After: types.StringValue("c")}, err: true},
+		"after the first": {plan: valueTargetingRuleResourceModel{After: types.StringValue("a")}, want: []string{"a", "new", "b"}},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.plan.insert(rules, model.ValueTargetingRule{Name: "new"})
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, but got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(got))
+			for _, rule := range got {
+				names = append(names, rule.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Fatalf("expected %q, but got %q", tt.want, names)
+			}
+			if len(rules) != 2 {
+				t.Fatalf("expected the rules to be left as they are, but got %v", rules)
+			}
+		})
+	}
+}
+
+func TestValueTargetingRuleResource_ModifyTargetingConflict(t *testing.T) {
+	t.Parallel()
+	m, client := newTargetingRuleMock(t, booleanTestdata)
+	m.concurrent = func(v *model.Value) {
+		v.Targeting.Rules = append(v.Targeting.Rules, model.ValueTargetingRule{Name: "other", Variant: "off", Expr: "true"})
+	}
+
+	r := &ValueTargetingRuleResource{c: client}
+	_, err := r.modifyTargeting(context.Background(), "test-bool-value", func(v *model.Value) error {
+		v.Targeting.Rules = append([]model.ValueTargetingRule{{Name: "mine", Variant: "on", Expr: "true"}}, v.Targeting.Rules...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.ruleNames(), []string{"mine", "", "", "other"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the concurrent change to be kept, but got %q", got)
+	}
+}
+
+func TestValueTargetingRuleResource_ModifyTargetingRejected(t *testing.T) {
+	t.Parallel()
+	m, client := newTargetingRuleMock(t, booleanTestdata)
+	m.rejected = true
+
+	r := &ValueTargetingRuleResource{c: client}
+	_, err := r.modifyTargeting(context.Background(), "test-bool-value", func(v *model.Value) error {
+		v.Targeting.Rules = append(v.Targeting.Rules, model.ValueTargetingRule{Name: "mine", Variant: "on", Expr: "true"})
+		return nil
+	})
+	if !edgeclient.IsConflict(err) {
+		t.Fatalf("expected the rejection to be returned, but got %v", err)
+	}
+	if m.updates != 1 {
+		t.Fatalf("expected a single update of an unchanged Value, but got %d", m.updates)
+	}
+}
+
+func TestValueTargetingRuleResource_ModifyTargetingParallel(t *testing.T) {
+	t.Parallel()
+	m, client := newTargetingRuleMock(t, booleanTestdata)
+	// Every update is written within the same second, so revisions cannot tell them apart.
+	m.value.UpdateTime = "1682089900"
+	m.readDelay = 10 * time.Millisecond
+
+	r := &ValueTargetingRuleResource{c: client}
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(names))
+	for _, name := range names {
+		name := name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.modifyTargeting(context.Background(), "test-bool-value", func(v *model.Value) error {
+				v.Targeting.Rules = append(v.Targeting.Rules, model.ValueTargetingRule{Name: name, Variant: "on", Expr: "true"})
+				return nil
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := map[string]bool{}
+	for _, name := range m.ruleNames() {
+		got[name] = true
+	}
+	for _, name := range names {
+		if !got[name] {
+			t.Errorf("expected targeting rule %s to be kept, but got %q", name, m.ruleNames())
+		}
+	}
+}
+
+func TestAccResourceEdgeValueTargetingRule_Parallel(t *testing.T) {
+	m, client := newTargetingRuleMock(t, booleanTestdata)
+	m.value.UpdateTime = "1682089900"
+	m.readDelay = 100 * time.Millisecond
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "edge_value_targeting_rule" "a" {
+  value_id = "test-bool-value"
+  name = "tenant-a"
+  variant = "on"
+  expr = "tenant == 'a'"
+  priority = 0
+}
+
+resource "edge_value_targeting_rule" "b" {
+  value_id = "test-bool-value"
+  name = "tenant-b"
+  variant = "off"
+  expr = "tenant == 'b'"
+  priority = 0
+}`,
+				Check: func(*terraform.State) error {
+					names := m.ruleNames()
+					if len(names) != 4 || !reflect.DeepEqual(names[2:], []string{"", ""}) {
+						return fmt.Errorf("expected both targeting rules before the existing ones, but got %q", names)
+					}
+					return nil
+				},
+			},
+		},
+		CheckDestroy: m.checkRuleNames("", ""),
+	})
+}
+
+func TestAccResourceEdgeValueTargetingRule_Owner(t *testing.T) {
+	owners := map[string]string{
+		"edge_value":         testAccResourceBoolean(),
+		"edge_boolean_value": testAccResourceTypedBoolean(),
+	}
+	for typ, owner := range owners {
+		owner := owner
+		t.Run(typ, func(t *testing.T) {
+			m, client := newTargetingRuleMock(t, booleanTestdata)
+			rule := fmt.Sprintf(`
+resource "edge_value_targeting_rule" "a" {
+  value_id = %s.test-bool-value.value_id
+  name = "tenant-a"
+  variant = "on"
+  expr = "tenant == 'a'"
+  priority = 1
+}`, typ)
+			updated := strings.Replace(owner, `description = "test bool value"`, `description = "updated bool value"`, 1)
+
+			// The owner keeps the rule it does not declare, applied after it or not.
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+				Steps: []resource.TestStep{
+					{
+						Config: providerConfig + owner + rule,
+						Check:  m.checkRuleNames("", "tenant-a", ""),
+					},
+					{
+						Config: providerConfig + updated + rule,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							m.checkRuleNames("", "tenant-a", ""),
+							resource.TestCheckResourceAttr(typ+".test-bool-value", "targeting.#", "2"),
+						),
+					},
+					{
+						Config:   providerConfig + updated + rule,
+						PlanOnly: true,
+					},
+				},
+			})
+		})
+	}
+}
+
+func testAccResourceTargetingRules(expr string) string {
+	return fmt.Sprintf(`
+resource "edge_value_targeting_rule" "a" {
+  value_id = "test-bool-value"
+  name = "tenant-a"
+  variant = "on"
+  expr = "tenant == 'a'"
+  priority = 0
+}
+
+resource "edge_value_targeting_rule" "b" {
+  value_id = "test-bool-value"
+  name = "tenant-b"
+  variant = "off"
+  expr = %q
+  after = edge_value_targeting_rule.a.name
+}`, expr)
+}
@@ -1,12 +1,22 @@
 package provider
 
 import (
+	"context"
 	_ "embed"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
+	"github.com/ca-irvine/terraform-provider-edge/internal/edgeclient"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/jarcoal/httpmock"
 )
 
@@ -36,18 +46,15 @@ func TestAccResourceEdgeValue_BooleanValue(t *testing.T) {
 		httpmock.NewStringResponder(200, booleanTestdata),
 	)
 
-	client := &http.Client{
-		Transport: mock,
-	}
-	cfg := &config{
-		m:        &sync.Mutex{},
-		endpoint: "http://localhost:8018",
-		client:   client,
-	}
-	client.Transport = mock
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
 
 	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: protoV6ProviderFactories(cfg),
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
 		Steps: []resource.TestStep{
 			{
 				Config: providerConfig + testAccResourceBoolean(),
@@ -56,11 +63,10 @@ func TestAccResourceEdgeValue_BooleanValue(t *testing.T) {
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "enabled", "true"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "description", "test bool value"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "default_variant", "off"),
-					resource.TestCheckResourceAttr("edge_value.test-bool-value", "boolean_value.#", "2"),
-					resource.TestCheckResourceAttr("edge_value.test-bool-value", "boolean_value.0.variant", "on"),
-					resource.TestCheckResourceAttr("edge_value.test-bool-value", "boolean_value.0.value", "true"),
-					resource.TestCheckResourceAttr("edge_value.test-bool-value", "boolean_value.1.variant", "off"),
-					resource.TestCheckResourceAttr("edge_value.test-bool-value", "boolean_value.1.value", "false"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "type", "boolean"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "variants.%", "2"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "variants.on.boolean_value", "true"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "variants.off.boolean_value", "false"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.#", "2"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.0.variant", "on"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.0.spec", "cel"),
@@ -69,7 +75,7 @@ func TestAccResourceEdgeValue_BooleanValue(t *testing.T) {
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.1.spec", "cel"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.1.expr", "userId == 'XXX'"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "test.#", "1"),
-					resource.TestCheckResourceAttr("edge_value.test-bool-value", "test.0.variables", "{\"count\":1,\"env\":\"test\"}"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "test.0.variables", "{\"count\":1,\"env\":\"dev\"}"),
 					resource.TestCheckResourceAttr("edge_value.test-bool-value", "test.0.expected", "on"),
 				),
 			},
@@ -103,18 +109,15 @@ func TestAccResourceEdgeValue_StringValue(t *testing.T) {
 		httpmock.NewStringResponder(200, stringTestdata),
 	)
 
-	client := &http.Client{
-		Transport: mock,
-	}
-	cfg := &config{
-		m:        &sync.Mutex{},
-		endpoint: "http://localhost:8018",
-		client:   client,
-	}
-	client.Transport = mock
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
 
 	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: protoV6ProviderFactories(cfg),
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
 		Steps: []resource.TestStep{
 			{
 				Config: providerConfig + testAccResourceString(),
@@ -123,9 +126,9 @@ func TestAccResourceEdgeValue_StringValue(t *testing.T) {
 					resource.TestCheckResourceAttr("edge_value.test-string-value", "enabled", "true"),
 					resource.TestCheckResourceAttr("edge_value.test-string-value", "description", "test string value"),
 					resource.TestCheckResourceAttr("edge_value.test-string-value", "default_variant", "key"),
-					resource.TestCheckResourceAttr("edge_value.test-string-value", "string_value.#", "1"),
-					resource.TestCheckResourceAttr("edge_value.test-string-value", "string_value.0.variant", "key"),
-					resource.TestCheckResourceAttr("edge_value.test-string-value", "string_value.0.value", "test value"),
+					resource.TestCheckResourceAttr("edge_value.test-string-value", "type", "string"),
+					resource.TestCheckResourceAttr("edge_value.test-string-value", "variants.%", "1"),
+					resource.TestCheckResourceAttr("edge_value.test-string-value", "variants.key.string_value", "test value"),
 					resource.TestCheckResourceAttr("edge_value.test-string-value", "targeting.#", "0"),
 				),
 			},
@@ -159,18 +162,15 @@ func TestAccResourceEdgeValue_JSONValue(t *testing.T) {
 		httpmock.NewStringResponder(200, jsonTestdata),
 	)
 
-	client := &http.Client{
-		Transport: mock,
-	}
-	cfg := &config{
-		m:        &sync.Mutex{},
-		endpoint: "http://localhost:8018",
-		client:   client,
-	}
-	client.Transport = mock
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
 
 	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: protoV6ProviderFactories(cfg),
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
 		Steps: []resource.TestStep{
 			{
 				Config: providerConfig + testAccResourceJSON(),
@@ -179,14 +179,14 @@ func TestAccResourceEdgeValue_JSONValue(t *testing.T) {
 					resource.TestCheckResourceAttr("edge_value.test-json-value", "enabled", "true"),
 					resource.TestCheckResourceAttr("edge_value.test-json-value", "description", "test json value"),
 					resource.TestCheckResourceAttr("edge_value.test-json-value", "default_variant", "json"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.#", "1"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.variant", "json"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.value", "{\"items\":[{\"content\":\"content1\",\"viewable\":true},{\"content\":\"content2\",\"viewable\":true},{\"content\":\"content3\",\"viewable\":false}]}"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.transform.#", "2"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.transform.0.spec", "cel"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.transform.0.expr", "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.transform.1.spec", "cel"),
-					resource.TestCheckResourceAttr("edge_value.test-json-value", "json_value.0.transform.1.expr", "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "type", "json"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.%", "1"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.json.json_value", "{\"items\":[{\"content\":\"content1\",\"viewable\":true},{\"content\":\"content2\",\"viewable\":true},{\"content\":\"content3\",\"viewable\":false}]}"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.json.transform.#", "2"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.json.transform.0.spec", "cel"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.json.transform.0.expr", "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.json.transform.1.spec", "cel"),
+					resource.TestCheckResourceAttr("edge_value.test-json-value", "variants.json.transform.1.expr", "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"),
 					resource.TestCheckResourceAttr("edge_value.test-json-value", "targeting.#", "0"),
 				),
 			},
@@ -194,6 +194,142 @@ func TestAccResourceEdgeValue_JSONValue(t *testing.T) {
 	})
 }
 
+func TestAccResourceEdgeValue_JSONHeredoc(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, jsonTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, jsonTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, jsonTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	// The Edge API returns the keys in another order and formatting, which must not show up
+	// as a diff once applied.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceJSONHeredoc(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("edge_value.test-json-value", "variants.json.json_value", regexp.MustCompile(`\{ "content": "content1", "viewable": true \}`)),
+				),
+			},
+			{
+				Config:   providerConfig + testAccResourceJSONHeredoc(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+//go:embed testdata/json_array.json
+var jsonArrayTestdata string
+
+func TestAccResourceEdgeValue_JSONNumberSpelling(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, jsonArrayTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, jsonArrayTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, jsonArrayTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	// The Edge API spells the numbers 1.0 and 2e0 of the configuration as 1 and 2, which must
+	// not show up as a diff.
+	config := strings.Replace(testAccResourceJSONArray(), `jsonencode([
+        {"id": 1, "title": "spring"},
+        {"id": 2, "title": "summer"}
+      ])`, `"[{\"id\": 1.0, \"title\": \"spring\"}, {\"id\": 2e0, \"title\": \"summer\"}]"`, 1)
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-json-array-value", "variants.banners.json_value", `[{"id": 1.0, "title": "spring"}, {"id": 2e0, "title": "summer"}]`),
+				),
+			},
+			{
+				Config:   providerConfig + config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_JSONArrayValue(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, jsonArrayTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, jsonArrayTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, jsonArrayTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceJSONArray(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-json-array-value", "type", "json"),
+					resource.TestCheckResourceAttr("edge_value.test-json-array-value", "variants.%", "2"),
+					resource.TestCheckResourceAttr("edge_value.test-json-array-value", "variants.banners.json_value", "[{\"id\":1,\"title\":\"spring\"},{\"id\":2,\"title\":\"summer\"}]"),
+					resource.TestCheckResourceAttr("edge_value.test-json-array-value", "variants.limit.json_value", "9007199254740993"),
+				),
+			},
+		},
+	})
+}
+
 //go:embed testdata/integer.json
 var integerTestdata string
 
@@ -220,18 +356,15 @@ func TestAccResourceEdgeValue_IntegerValue(t *testing.T) {
 		httpmock.NewStringResponder(200, integerTestdata),
 	)
 
-	client := &http.Client{
-		Transport: mock,
-	}
-	cfg := &config{
-		m:        &sync.Mutex{},
-		endpoint: "http://localhost:8018",
-		client:   client,
-	}
-	client.Transport = mock
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
 
 	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: protoV6ProviderFactories(cfg),
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
 		Steps: []resource.TestStep{
 			{
 				Config: providerConfig + testAccResourceInteger(),
@@ -240,9 +373,11 @@ func TestAccResourceEdgeValue_IntegerValue(t *testing.T) {
 					resource.TestCheckResourceAttr("edge_value.test-integer-value", "enabled", "true"),
 					resource.TestCheckResourceAttr("edge_value.test-integer-value", "description", "test integer value"),
 					resource.TestCheckResourceAttr("edge_value.test-integer-value", "default_variant", "one"),
-					resource.TestCheckResourceAttr("edge_value.test-integer-value", "integer_value.#", "1"),
-					resource.TestCheckResourceAttr("edge_value.test-integer-value", "integer_value.0.variant", "one"),
-					resource.TestCheckResourceAttr("edge_value.test-integer-value", "integer_value.0.value", "1"),
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "type", "integer"),
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "create_time", "2023-04-19T08:58:50Z"),
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "update_time", "2023-04-21T15:08:54Z"),
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "variants.%", "1"),
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "variants.one.integer_value", "1"),
 					resource.TestCheckResourceAttr("edge_value.test-integer-value", "targeting.#", "0"),
 				),
 			},
@@ -250,101 +385,1115 @@ func TestAccResourceEdgeValue_IntegerValue(t *testing.T) {
 	})
 }
 
-func testAccResourceBoolean() string {
-	return `
-resource "edge_value" "test-bool-value" {
-  value_id = "test-bool-value"
-  enabled = true
-  description = "test bool value"
-  default_variant = "off"
-
-  boolean_value {
-	variant = "on"
-	value = true
-  }
+//go:embed testdata/number.json
+var numberTestdata string
 
-  boolean_value {
-	variant = "off"
-	value = false
-  }
+func TestAccResourceEdgeValue_NumberValue(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, numberTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, numberTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		httpmock.NewStringResponder(200, numberTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, numberTestdata),
+	)
 
-  targeting {
-    variant = "on"
-    spec = "cel"
-    expr = "env == 'dev'"
-  }
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
 
-  targeting {
-    variant = "on"
-    spec = "cel"
-    expr = "userId == 'XXX'"
-  }
-	
-  test {
-	variables = jsonencode({
-	  env = "test"
-	  count = 1
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceNumber(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "value_id", "test-number-value"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "default_variant", "sampled"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "type", "number"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "create_time", "2023-04-19T08:58:50Z"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "update_time", "2023-04-21T15:08:54Z"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "variants.%", "2"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "variants.sampled.number_value", "0.125"),
+					resource.TestCheckResourceAttr("edge_value.test-number-value", "variants.precise.number_value", "1.00000000000000000001"),
+				),
+			},
+		},
 	})
-	expected = "on"
-  }
-}`
 }
 
-func testAccResourceString() string {
-	return `
-resource "edge_value" "test-string-value" {
-  value_id = "test-string-value"
+func TestAccResourceEdgeValue_VariantValues(t *testing.T) {
+	// The Edge API stores what it is sent.
+	var stored model.Value
+	store := func(req *http.Request) (*http.Response, error) {
+		stored = model.Value{}
+		if err := json.NewDecoder(req.Body).Decode(&stored); err != nil {
+			return nil, err
+		}
+		stored.UpdateTime = "1682089734"
+		return httpmock.NewJsonResponse(200, &stored)
+	}
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(http.MethodPost, "http://localhost:8018/service.Value/Create", store)
+	mock.RegisterResponder(http.MethodPost, "http://localhost:8018/service.Value/Update", store)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(200, &stored)
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, "{}"),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	// checkStored compares the set field of the stored variant with want.
+	checkStored := func(variant, want string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			var fields map[string]any
+			b, _ := json.Marshal(stored.Variants[variant])
+			if err := json.Unmarshal(b, &fields); err != nil {
+				return err
+			}
+			for k, v := range fields {
+				if v == nil {
+					delete(fields, k)
+				}
+			}
+			if b, _ = json.Marshal(fields); string(b) != want {
+				return fmt.Errorf("expected variant %s to be stored as %s, but got %s", variant, want, b)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceVariantValues("true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-dynamic-value", "type", "json"),
+					resource.TestCheckNoResourceAttr("edge_value.test-dynamic-value", "variants.%"),
+					checkStored("items", `{"jsonValue":{"value":{"items":[{"content":"content1","viewable":true}]}}}`),
+					checkStored("empty", `{"jsonValue":{"value":[]}}`),
+				),
+			},
+			{
+				Config: providerConfig + testAccResourceVariantValues("false"),
+				Check:  checkStored("items", `{"jsonValue":{"value":{"items":[{"content":"content1","viewable":false}]}}}`),
+			},
+			{
+				Config: providerConfig + `
+resource "edge_value" "test-dynamic-value" {
+  value_id = "test-dynamic-value"
   enabled = true
-  description = "test string value"
-  default_variant = "key"
+  default_variant = "one"
 
-  string_value {
-	variant = "key"
-	value = "test value"
+  variant_values = {
+    one = 1
+    half = 0.5
   }
-}`
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-dynamic-value", "type", "number"),
+					checkStored("one", `{"numberValue":{"value":1}}`),
+				),
+			},
+		},
+	})
 }
 
-func testAccResourceJSON() string {
-	return `
-resource "edge_value" "test-json-value" {
-  value_id = "test-json-value"
-  enabled = true
-  description = "test json value"
-  default_variant = "json"
+func TestAccResourceEdgeValue_ConflictingVariantValues(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + strings.Replace(testAccResourceBoolean(), "enabled = true", "enabled = true\n  variant_values = { on = true }", 1),
+				ExpectError: regexp.MustCompile(`Only one of variants and variant_values can be set`),
+			},
+		},
+	})
+}
 
-  json_value {
-	variant = "json"
-	value = jsonencode({
-	  "items": [
-		{"viewable": true, "content": "content1"},
-		{"viewable": true, "content": "content2"},
-		{"viewable": false, "content": "content3"}
-	  ]	
-	})
-	transform {
-	  spec = "cel"
-	  expr = "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"
-	}
-	transform {
-	  spec = "cel"
-	  expr = "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"
-	}
-  }
-}`
+func TestAccResourceEdgeValue_Conflict(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, integerTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(200, integerTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		func(req *http.Request) (*http.Response, error) {
+			if got := req.Header.Get("If-Match"); got != `"1682089734"` {
+				t.Errorf("expected If-Match to be the last read update time, but got %q", got)
+			}
+			return httpmock.NewStringResponse(400, `{"code":"failed_precondition","message":"value test-integer-value was updated","details":[{"type":"edge.v1.ConflictInfo","value":"","debug":{"actor":"someone@example.com"}}]}`), nil
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, integerTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceInteger(),
+			},
+			{
+				Config:      providerConfig + strings.Replace(testAccResourceInteger(), "integer_value = 1", "integer_value = 2", 1),
+				ExpectError: regexp.MustCompile(`(?s)Value Changed Concurrently.*changed\s+by\s+someone@example.com`),
+			},
+		},
+	})
 }
 
-func testAccResourceInteger() string {
-	return `
-resource "edge_value" "test-integer-value" {
-  value_id = "test-integer-value"
-  enabled = true
-  description = "test integer value"
-  default_variant = "one"
+func TestAccResourceEdgeValue_ConflictSameRevision(t *testing.T) {
+	var mu sync.Mutex
+	var stored model.Value
+	if err := json.Unmarshal([]byte(booleanTestdata), &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored.UpdateTime = "1682089734"
+
+	mock := httpmock.NewMockTransport()
+	for _, method := range []string{"Create", "Get", "Update"} {
+		mock.RegisterResponder(
+			http.MethodPost,
+			"http://localhost:8018/service.Value/"+method,
+			func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return httpmock.NewJsonResponse(200, &stored)
+			},
+		)
+	}
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	// The Value is changed within the second it was read in, so its update time stays the same
+	// and the Edge API would accept the update.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceBoolean(),
+			},
+			{
+				Config: providerConfig + strings.Replace(testAccResourceBoolean(), `default_variant = "off"`, `default_variant = "on"`, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{planCheckFunc(func() {
+						mu.Lock()
+						defer mu.Unlock()
+						stored.Description = "changed in the Edge UI"
+					})},
+				},
+				ExpectError: regexp.MustCompile(`(?s)Value Changed Concurrently.*did not report who changed\s+it.*description changed`),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_UnknownFields(t *testing.T) {
+	// The Edge API keeps fields set from the Edge UI that the provider does not model, on the
+	// Value and on its variants.
+	stored := strings.NewReplacer(
+		`"enabled": true,`, `"enabled": true, "owner": {"team": "growth"},`,
+		`"one": {`, `"one": {"rollout": {"percent": 50},`,
+	).Replace(integerTestdata)
+
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, integerTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, stored), nil
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		func(req *http.Request) (*http.Response, error) {
+			var in map[string]any
+			if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+				return nil, err
+			}
+			if _, ok := in["owner"]; !ok {
+				t.Errorf("expected the owner field to be written back, but got %v", in)
+			}
+			if one, _ := in["variants"].(map[string]any)["one"].(map[string]any); one["rollout"] == nil {
+				t.Errorf("expected the rollout field of the variant to be written back, but got %v", in)
+			}
+			in["createTime"] = 1681894730
+			in["updateTime"] = 1682089800
+			b, err := json.Marshal(in)
+			if err != nil {
+				return nil, err
+			}
+			stored = string(b)
+			return httpmock.NewStringResponse(200, stored), nil
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, integerTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceInteger(),
+			},
+			{
+				Config: providerConfig + strings.Replace(testAccResourceInteger(), "integer_value = 1", "integer_value = 2", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "variants.one.integer_value", "2"),
+					resource.TestCheckResourceAttr("edge_value.test-integer-value", "update_time", "2023-04-21T15:10:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_ManagedFields(t *testing.T) {
+	// The targeting rules of booleanTestdata are edited in the Edge UI.
+	stored := booleanTestdata
+
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, stored), nil
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		func(req *http.Request) (*http.Response, error) {
+			var in model.Value
+			if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+				return nil, err
+			}
+			if len(in.Targeting.Rules) != 2 {
+				t.Errorf("expected the targeting rules of the Edge API to be kept, but got %v", in.Targeting.Rules)
+			}
+			b, err := json.Marshal(&in)
+			if err != nil {
+				return nil, err
+			}
+			stored = string(b)
+			return httpmock.NewStringResponse(200, stored), nil
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceManagedVariants("test bool value"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "managed_fields.#", "1"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.#", "0"),
+				),
+			},
+			{
+				Config: providerConfig + testAccResourceManagedVariants("updated bool value"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "description", "updated bool value"),
+					resource.TestCheckResourceAttr("edge_value.test-bool-value", "targeting.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_ManagedFieldsConflict(t *testing.T) {
+	var mu sync.Mutex
+	var stored model.Value
+	if err := json.Unmarshal([]byte(booleanTestdata), &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored.UpdateTime = "1682089734"
+	// change edits the stored Value as the Edge UI would between a plan and its apply.
+	updateTime := 1682089800
+	change := func(edit func(*model.Value)) resource.ConfigPlanChecks {
+		return resource.ConfigPlanChecks{
+			PreApply: []plancheck.PlanCheck{planCheckFunc(func() {
+				mu.Lock()
+				defer mu.Unlock()
+				edit(&stored)
+				updateTime++
+				stored.UpdateTime = json.Number(strconv.Itoa(updateTime))
+			})},
+		}
+	}
+
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return httpmock.NewJsonResponse(200, &stored)
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return httpmock.NewJsonResponse(200, &stored)
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Update",
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			var in model.Value
+			if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+				return nil, err
+			}
+			in.UpdateTime = "1682089900"
+			stored = in
+			return httpmock.NewJsonResponse(200, &stored)
+		},
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(200, booleanTestdata),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccResourceManagedVariants("test bool value"),
+			},
+			{
+				// A change of the targeting rules, which are managed elsewhere, is kept.
+				Config: providerConfig + testAccResourceManagedVariants("updated bool value"),
+				ConfigPlanChecks: change(func(v *model.Value) {
+					v.Targeting.Rules = v.Targeting.Rules[:1]
+				}),
+				Check: func(*terraform.State) error {
+					mu.Lock()
+					defer mu.Unlock()
+					if len(stored.Targeting.Rules) != 1 || stored.Description != "updated bool value" {
+						return fmt.Errorf("expected the description to be updated and a single targeting rule, but got %+v", stored)
+					}
+					return nil
+				},
+			},
+			{
+				// A change of the variants, which the resource owns, is not overwritten.
+				Config: providerConfig + testAccResourceManagedVariants("test bool value"),
+				ConfigPlanChecks: change(func(v *model.Value) {
+					v.DefaultVariant = "on"
+				}),
+				ExpectError: regexp.MustCompile(`(?s)Value Changed Concurrently.*variants changed`),
+			},
+			{
+				// Neither is a change of enabled, which is always owned.
+				Config: providerConfig + testAccResourceManagedVariants("test bool value"),
+				ConfigPlanChecks: change(func(v *model.Value) {
+					v.Enabled = false
+				}),
+				ExpectError: regexp.MustCompile(`(?s)Value Changed Concurrently.*enabled changed`),
+			},
+		},
+	})
+}
+
+// planCheckFunc runs a function between a plan and its apply.
+type planCheckFunc func()
+
+func (f planCheckFunc) CheckPlan(context.Context, plancheck.CheckPlanRequest, *plancheck.CheckPlanResponse) {
+	f()
+}
+
+func TestAccResourceEdgeValue_CreateUnmanagedVariants(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "edge_value" "test-bool-value" {
+  value_id = "test-bool-value"
+  enabled = true
+  managed_fields = ["targeting"]
+}`,
+				ExpectError: regexp.MustCompile(`(?s)Unmanaged Variants.*import`),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_UnmanagedAttribute(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + strings.Replace(testAccResourceBoolean(), "enabled = true", "enabled = true\n  managed_fields = [\"variants\"]", 1),
+				ExpectError: regexp.MustCompile(`(?s)Unmanaged Attribute.*managed_fields does not\s+include "targeting"`),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_Removed(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Create",
+		httpmock.NewStringResponder(200, stringTestdata),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(404, `{"code":"not_found","message":"value not found"}`),
+	)
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Delete",
+		httpmock.NewStringResponder(404, `{"code":"not_found","message":"value not found"}`),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config:             providerConfig + testAccResourceString(),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_ImportNotFound(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(
+		http.MethodPost,
+		"http://localhost:8018/service.Value/Get",
+		httpmock.NewStringResponder(404, `{"code":"not_found","message":"value not found"}`),
+	)
+
+	client := edgeclient.New(edgeclient.Config{
+		Endpoint: "http://localhost:8018",
+		HTTPClient: &http.Client{
+			Transport: mock,
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+		Steps: []resource.TestStep{
+			{
+				Config:        providerConfig + testAccResourceString(),
+				ResourceName:  "edge_value.test-string-value",
+				ImportState:   true,
+				ImportStateId: "test-string-value",
+				ExpectError:   regexp.MustCompile("value test-string-value does not exist"),
+			},
+		},
+	})
+}
+
+var (
+	//go:embed testdata/integer_import.json
+	integerImportTestdata string
+	//go:embed testdata/string_import.json
+	stringImportTestdata string
+)
+
+func TestAccResourceEdgeValue_ImportEncodings(t *testing.T) {
+	// The Edge API encodes integers as strings past the precision of a JSON number, and leaves
+	// out zero values.
+	tests := map[string]struct {
+		data   string
+		id     string
+		config string
+		want   map[string]string
+	}{
+		"integer": {
+			data:   integerImportTestdata,
+			id:     "test-integer-value",
+			config: testAccResourceInteger(),
+			want: map[string]string{
+				"variants.%":                  "3",
+				"variants.one.integer_value":  "1",
+				"variants.two.integer_value":  "2",
+				"variants.zero.integer_value": "0",
+			},
+		},
+		"string": {
+			data:   stringImportTestdata,
+			id:     "test-string-value",
+			config: testAccResourceString(),
+			want: map[string]string{
+				"variants.%":                 "2",
+				"variants.key.string_value":  "test value",
+				"variants.none.string_value": "",
+			},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			mock := httpmock.NewMockTransport()
+			mock.RegisterResponder(
+				http.MethodPost,
+				"http://localhost:8018/service.Value/Get",
+				httpmock.NewStringResponder(200, tt.data),
+			)
+
+			client := edgeclient.New(edgeclient.Config{
+				Endpoint: "http://localhost:8018",
+				HTTPClient: &http.Client{
+					Transport: mock,
+				},
+			})
+
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: protoV6ProviderFactories(client),
+				Steps: []resource.TestStep{
+					{
+						Config:        providerConfig + tt.config,
+						ResourceName:  "edge_value." + tt.id,
+						ImportState:   true,
+						ImportStateId: tt.id,
+						ImportStateCheck: func(states []*terraform.InstanceState) error {
+							if len(states) != 1 {
+								return fmt.Errorf("expected a single imported value, but got %d", len(states))
+							}
+							for k, want := range tt.want {
+								if got, ok := states[0].Attributes[k]; !ok || got != want {
+									return fmt.Errorf("expected %s to be %q, but got %q", k, want, got)
+								}
+							}
+							return nil
+						},
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccResourceEdgeValue_InvalidExpr(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccResourceInvalidTargeting(),
+				ExpectError: regexp.MustCompile(`(?s)Invalid CEL Expression.*<input>:1:5`),
+			},
+			{
+				Config:      providerConfig + testAccResourceInvalidTransform(),
+				ExpectError: regexp.MustCompile(`(?s)Invalid CEL Expression.*undeclared reference to 'dropKey'`),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_FailingTest(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccResourceFailingTest(),
+				ExpectError: regexp.MustCompile(`(?s)Value Test Failed.*test\[0\] expected variant "on"`),
+			},
+			{
+				// A rule failing on a missing variable does not match, and the failure tells why.
+				Config:      providerConfig + strings.Replace(testAccResourceFailingTest(), `env = "prd"`, `userId = "XXX"`, 1),
+				ExpectError: regexp.MustCompile(`(?s)Value Test Failed.*no targeting rule matched.*targeting\[0\] was skipped because it\s+failed\s+to\s+evaluate`),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_UndeclaredVariant(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccResourceUndeclaredVariant(),
+				ExpectError: regexp.MustCompile(`(?s)Undeclared Variant.*default_variant refers to variant "none"`),
+			},
+			{
+				Config:      providerConfig + testAccResourceUndeclaredVariant(),
+				ExpectError: regexp.MustCompile(`(?s)Undeclared Variant.*targeting rule refers to variant "maybe"`),
+			},
+			{
+				Config:      providerConfig + testAccResourceUndeclaredVariant(),
+				ExpectError: regexp.MustCompile(`(?s)Undeclared Variant.*test refers to variant "yes"`),
+			},
+		},
+	})
+}
+
+func TestAccResourceEdgeValue_TypeMismatch(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccResourceMixedTypes(""),
+				ExpectError: regexp.MustCompile(`(?s)Variant Type Mismatch.*variant "text" is a\s+string\s+value\s+while\s+variant\s+"on"\s+is\s+a\s+boolean\s+value`),
+			},
+			{
+				Config:      providerConfig + testAccResourceMixedTypes(`type = "string"`),
+				ExpectError: regexp.MustCompile(`(?s)Variant Type Mismatch.*variant "on" is\s+a\s+boolean\s+value\s+on\s+a\s+value\s+of\s+type\s+"string"`),
+			},
+		},
+	})
+}
+
+func testAccResourceBoolean() string {
+	return `
+resource "edge_value" "test-bool-value" {
+  value_id = "test-bool-value"
+  enabled = true
+  description = "test bool value"
+  default_variant = "off"
+
+  variants = {
+    on = {
+      boolean_value = true
+    }
+    off = {
+      boolean_value = false
+    }
+  }
+
+  targeting {
+    variant = "on"
+    spec = "cel"
+    expr = "env == 'dev'"
+  }
+
+  targeting {
+    variant = "on"
+    spec = "cel"
+    expr = "userId == 'XXX'"
+  }
+	
+  test {
+	variables = jsonencode({
+	  env = "dev"
+	  count = 1
+	})
+	expected = "on"
+  }
+}`
+}
+
+func testAccResourceManagedVariants(description string) string {
+	return fmt.Sprintf(`
+resource "edge_value" "test-bool-value" {
+  value_id = "test-bool-value"
+  enabled = true
+  description = %q
+  default_variant = "off"
+  managed_fields = ["variants"]
+
+  variants = {
+    on = {
+      boolean_value = true
+    }
+    off = {
+      boolean_value = false
+    }
+  }
+
+  test {
+    variables = jsonencode({
+      env = "dev"
+      count = 1
+    })
+    expected = "on"
+  }
+}`, description)
+}
+
+func testAccResourceVariantValues(viewable string) string {
+	return fmt.Sprintf(`
+resource "edge_value" "test-dynamic-value" {
+  value_id = "test-dynamic-value"
+  enabled = true
+  default_variant = "items"
+
+  variant_values = {
+    items = {
+      items = [
+        {
+          content = "content1"
+          viewable = %s
+        },
+      ]
+    }
+    empty = []
+  }
+}`, viewable)
+}
+
+func testAccResourceString() string {
+	return `
+resource "edge_value" "test-string-value" {
+  value_id = "test-string-value"
+  enabled = true
+  description = "test string value"
+  default_variant = "key"
+
+  variants = {
+    key = {
+      string_value = "test value"
+    }
+  }
+}`
+}
+
+func testAccResourceJSON() string {
+	return `
+resource "edge_value" "test-json-value" {
+  value_id = "test-json-value"
+  enabled = true
+  description = "test json value"
+  default_variant = "json"
+
+  variants = {
+    json = {
+      json_value = jsonencode({
+        "items": [
+          {"viewable": true, "content": "content1"},
+          {"viewable": true, "content": "content2"},
+          {"viewable": false, "content": "content3"}
+        ]
+      })
+      transform = [
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"
+        },
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"
+        },
+      ]
+    }
+  }
+}`
+}
+
+func testAccResourceJSONArray() string {
+	return `
+resource "edge_value" "test-json-array-value" {
+  value_id = "test-json-array-value"
+  enabled = true
+  description = "test json array value"
+  default_variant = "banners"
+
+  variants = {
+    banners = {
+      json_value = jsonencode([
+        {"id": 1, "title": "spring"},
+        {"id": 2, "title": "summer"}
+      ])
+    }
+    limit = {
+      json_value = "9007199254740993"
+    }
+  }
+}`
+}
+
+func testAccResourceJSONHeredoc() string {
+	return `
+resource "edge_value" "test-json-value" {
+  value_id = "test-json-value"
+  enabled = true
+  description = "test json value"
+  default_variant = "json"
+
+  variants = {
+    json = {
+      json_value = <<-EOT
+        {
+          "items": [
+            { "content": "content1", "viewable": true },
+            { "viewable": true, "content": "content2" },
+            { "viewable": false, "content": "content3" }
+          ]
+        }
+      EOT
+      transform = [
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item : item.deleteKey([\"content\"]))}"
+        },
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.viewable ? item.selectKey([\"content\"]) : item)}"
+        },
+      ]
+    }
+  }
+}`
+}
+
+func testAccResourceInteger() string {
+	return `
+resource "edge_value" "test-integer-value" {
+  value_id = "test-integer-value"
+  enabled = true
+  description = "test integer value"
+  default_variant = "one"
+
+  variants = {
+    one = {
+      integer_value = 1
+    }
+  }
+}`
+}
+
+func testAccResourceNumber() string {
+	return `
+resource "edge_value" "test-number-value" {
+  value_id = "test-number-value"
+  enabled = true
+  description = "test number value"
+  default_variant = "sampled"
+
+  variants = {
+    sampled = {
+      number_value = 0.125
+    }
+    precise = {
+      number_value = 1.00000000000000000001
+    }
+  }
+}`
+}
+
+func testAccResourceInvalidTargeting() string {
+	return `
+resource "edge_value" "test-invalid-targeting" {
+  value_id = "test-invalid-targeting"
+  enabled = true
+  default_variant = "off"
+
+  variants = {
+    off = {
+      boolean_value = false
+    }
+  }
+
+  targeting {
+    variant = "off"
+    spec = "cel"
+    expr = "env = 'dev'"
+  }
+}`
+}
+
+func testAccResourceInvalidTransform() string {
+	return `
+resource "edge_value" "test-invalid-transform" {
+  value_id = "test-invalid-transform"
+  enabled = true
+  default_variant = "json"
+
+  variants = {
+    json = {
+      json_value = jsonencode({"items": []})
+      transform = [
+        {
+          spec = "cel"
+          expr = "{\"items\":items.map(item, item.dropKey([\"content\"]))}"
+        },
+      ]
+    }
+  }
+}`
+}
+
+func testAccResourceFailingTest() string {
+	return `
+resource "edge_value" "test-failing-test" {
+  value_id = "test-failing-test"
+  enabled = true
+  default_variant = "off"
+
+  variants = {
+    on = {
+      boolean_value = true
+    }
+    off = {
+      boolean_value = false
+    }
+  }
+
+  targeting {
+    variant = "on"
+    spec = "cel"
+    expr = "env == 'dev'"
+  }
+
+  test {
+	variables = jsonencode({
+	  env = "prd"
+	})
+	expected = "on"
+  }
+}`
+}
+
+func testAccResourceUndeclaredVariant() string {
+	return `
+resource "edge_value" "test-undeclared-variant" {
+  value_id = "test-undeclared-variant"
+  enabled = true
+  default_variant = "none"
+
+  variants = {
+    on = {
+      boolean_value = true
+    }
+  }
+
+  targeting {
+    variant = "maybe"
+    spec = "cel"
+    expr = "env == 'dev'"
+  }
+
+  test {
+	variables = jsonencode({
+	  env = "prd"
+	})
+	expected = "yes"
+  }
+}`
+}
+
+func testAccResourceMixedTypes(typ string) string {
+	return `
+resource "edge_value" "test-mixed-types" {
+  value_id = "test-mixed-types"
+  enabled = true
+  default_variant = "on"
+  ` + typ + `
 
-  integer_value {
-	variant = "one"
-	value = 1
+  variants = {
+    on = {
+      boolean_value = true
+    }
+    text = {
+      string_value = "on"
+    }
   }
 }`
 }
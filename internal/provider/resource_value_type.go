@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// valueTypeAttributes maps each variant type to the variant attribute holding values of that
+// type.
+var valueTypeAttributes = []struct {
+	typ  string
+	attr string
+}{
+	{typ: model.ValueTypeBoolean, attr: "boolean_value"},
+	{typ: model.ValueTypeString, attr: "string_value"},
+	{typ: model.ValueTypeJSON, attr: "json_value"},
+	{typ: model.ValueTypeInteger, attr: "integer_value"},
+	{typ: model.ValueTypeNumber, attr: "number_value"},
+}
+
+// configVariantTypes returns the type of every variant in config, keyed by variant name. A
+// variant setting no value, or several, has an empty type. It reports false when some variants
+// are not known yet.
+func configVariantTypes(ctx context.Context, config tfsdk.Config) (map[string]string, bool) {
+	if values := configVariantValues(ctx, config); !values.IsNull() {
+		var typ types.String
+		if d := config.GetAttribute(ctx, path.Root("type"), &typ); d.HasError() || typ.IsUnknown() {
+			return nil, false
+		}
+		variants, known, err := expandVariantValues(ctx, values, typ.ValueString())
+		if err != nil || !known {
+			return nil, false
+		}
+		typs := make(map[string]string, len(variants))
+		for k, v := range variants {
+			typs[k] = v.typ()
+		}
+		return typs, true
+	}
+
+	var variants types.Map
+	if d := config.GetAttribute(ctx, path.Root("variants"), &variants); d.HasError() || variants.IsUnknown() {
+		return nil, false
+	}
+	typs := make(map[string]string, len(variants.Elements()))
+	for k, e := range variants.Elements() {
+		obj, ok := e.(types.Object)
+		if !ok || obj.IsUnknown() {
+			return nil, false
+		}
+		var set []string
+		for _, a := range valueTypeAttributes {
+			v := obj.Attributes()[a.attr]
+			if v == nil || v.IsNull() {
+				continue
+			}
+			if v.IsUnknown() {
+				return nil, false
+			}
+			set = append(set, a.typ)
+		}
+		if len(set) == 1 {
+			typs[k] = set[0]
+		} else {
+			typs[k] = ""
+		}
+	}
+	return typs, true
+}
+
+// variantTypes returns the distinct variant types of v.
+func (v *valueResourceModel) variantTypes() []string {
+	typs := make([]string, 0, len(valueTypeAttributes))
+	for _, a := range valueTypeAttributes {
+		for _, variant := range v.Variants {
+			if variant.typ() == a.typ {
+				typs = append(typs, a.typ)
+				break
+			}
+		}
+	}
+	return typs
+}
+
+// variantType returns the type shared by every variant of v, or an empty string when there is
+// none.
+func (v *valueResourceModel) variantType() string {
+	if typs := v.variantTypes(); len(typs) == 1 {
+		return typs[0]
+	}
+	return ""
+}
+
+// validateValueType checks that every variant sets exactly one value, and that all variants
+// match the configured type, or share one type when none is configured.
+func validateValueType(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var typ types.String
+	if d := config.GetAttribute(ctx, path.Root("type"), &typ); d.HasError() || typ.IsUnknown() {
+		return
+	}
+	typs, known := configVariantTypes(ctx, config)
+	if !known {
+		return
+	}
+
+	names := make([]string, 0, len(typs))
+	for k := range typs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	values := configVariantValues(ctx, config)
+	var first string
+	for _, k := range names {
+		p := path.Root("variants").AtMapKey(k)
+		if !values.IsNull() {
+			p = path.Root("variant_values")
+		}
+		t := typs[k]
+		if t == "" {
+			diags.AddAttributeError(
+				p,
+				"Invalid Variant",
+				fmt.Sprintf("Variant %q must set exactly one of boolean_value, string_value, json_value, integer_value or number_value.", k),
+			)
+			continue
+		}
+		if t != model.ValueTypeJSON {
+			var transforms types.List
+			if d := config.GetAttribute(ctx, p.AtName("transform"), &transforms); !d.HasError() && !transforms.IsNull() {
+				diags.AddAttributeError(
+					p.AtName("transform"),
+					"Invalid Variant",
+					fmt.Sprintf("Variant %q sets transform, which only applies to json_value.", k),
+				)
+			}
+		}
+
+		switch {
+		case !typ.IsNull():
+			if t != typ.ValueString() {
+				diags.AddAttributeError(
+					p,
+					"Variant Type Mismatch",
+					fmt.Sprintf("A value only holds variants of one type, but variant %q is a %s value on a value of type %q.", k, t, typ.ValueString()),
+				)
+			}
+		case first == "":
+			first = k
+		case t != typs[first]:
+			diags.AddAttributeError(
+				p,
+				"Variant Type Mismatch",
+				fmt.Sprintf("A value only holds variants of one type, but variant %q is a %s value while variant %q is a %s value.", k, t, first, typs[first]),
+			)
+		}
+	}
+}
+
+// inferValueType sets an unconfigured type from the variants present in config.
+func inferValueType() planmodifier.String {
+	return inferValueTypeModifier{}
+}
+
+type inferValueTypeModifier struct{}
+
+func (m inferValueTypeModifier) Description(_ context.Context) string {
+	return "Defaults to the type of the variants."
+}
+
+func (m inferValueTypeModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m inferValueTypeModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+	typs, known := configVariantTypes(ctx, req.Config)
+	if !known {
+		return
+	}
+	var typ string
+	for _, t := range typs {
+		if t == "" || (typ != "" && t != typ) {
+			return
+		}
+		typ = t
+	}
+	if typ == "" {
+		if !req.StateValue.IsNull() {
+			resp.PlanValue = req.StateValue
+		}
+		return
+	}
+	resp.PlanValue = types.StringValue(typ)
+}
+
+// valueTypeState returns the type shared by the variants of v, or null when there is none.
+func valueTypeState(v *model.Value) types.String {
+	if typ := v.Type(); typ != "" {
+		return types.StringValue(typ)
+	}
+	return types.StringNull()
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithUpgradeState = &ValueResource{}
+
+// Version 0 of edge_value declared variants as boolean_value, string_value, json_value and
+// integer_value list blocks.
+type (
+	valueResourceModelV0 struct {
+		ID             types.String                     `tfsdk:"id"`
+		ValueID        types.String                     `tfsdk:"value_id"`
+		Description    types.String                     `tfsdk:"description"`
+		Enabled        types.Bool                       `tfsdk:"enabled"`
+		DefaultVariant types.String                     `tfsdk:"default_variant"`
+		Type           types.String                     `tfsdk:"type"`
+		BooleanValue   []valueResourceBooleanValueModel `tfsdk:"boolean_value"`
+		StringValue    []valueResourceStringValueModel  `tfsdk:"string_value"`
+		JSONValue      []valueResourceJSONValueModel    `tfsdk:"json_value"`
+		IntegerValue   []valueResourceIntegerValueModel `tfsdk:"integer_value"`
+		Targeting      []valueResourceTargetingModel    `tfsdk:"targeting"`
+		Test           []valueResourceTestModel         `tfsdk:"test"`
+		Timeouts       timeouts.Value                   `tfsdk:"timeouts"`
+	}
+
+	valueResourceBooleanValueModel struct {
+		Variant types.String `tfsdk:"variant"`
+		Value   types.Bool   `tfsdk:"value"`
+	}
+
+	valueResourceStringValueModel struct {
+		Variant types.String `tfsdk:"variant"`
+		Value   types.String `tfsdk:"value"`
+	}
+
+	valueResourceJSONValueModel struct {
+		Variant   types.String                  `tfsdk:"variant"`
+		Value     types.String                  `tfsdk:"value"`
+		Transform []valueResourceTransformModel `tfsdk:"transform"`
+	}
+
+	valueResourceIntegerValueModel struct {
+		Variant types.String `tfsdk:"variant"`
+		Value   types.Int64  `tfsdk:"value"`
+	}
+)
+
+func (v *ValueResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := valueResourceSchemaV0(ctx)
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schemaV0,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior valueResourceModelV0
+				diags := req.State.Get(ctx, &prior)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				diags = resp.State.Set(ctx, prior.upgrade())
+				resp.Diagnostics.Append(diags...)
+			},
+		},
+	}
+}
+
+// upgrade converts the variant blocks of m into the variants map of version 1.
+func (m *valueResourceModelV0) upgrade() *valueResourceModel {
+	variants := make(map[string]valueResourceVariantModel, len(m.BooleanValue)+len(m.StringValue)+len(m.JSONValue)+len(m.IntegerValue))
+	for _, b := range m.BooleanValue {
+		variants[b.Variant.ValueString()] = valueResourceVariantModel{BooleanValue: b.Value}
+	}
+	for _, b := range m.StringValue {
+		variants[b.Variant.ValueString()] = valueResourceVariantModel{StringValue: b.Value}
+	}
+	for _, b := range m.JSONValue {
+		variant := valueResourceVariantModel{JSONValue: jsontypes.Normalized{StringValue: b.Value}}
+		if len(b.Transform) > 0 {
+			variant.Transform = b.Transform
+		}
+		variants[b.Variant.ValueString()] = variant
+	}
+	for _, b := range m.IntegerValue {
+		variants[b.Variant.ValueString()] = valueResourceVariantModel{IntegerValue: b.Value}
+	}
+
+	state := &valueResourceModel{
+		ID:             m.ID,
+		ValueID:        m.ValueID,
+		Description:    m.Description,
+		Enabled:        m.Enabled,
+		DefaultVariant: m.DefaultVariant,
+		Type:           m.Type,
+		Variants:       variants,
+		Targeting:      m.Targeting,
+		Test:           m.Test,
+		ManagedFields:  types.SetNull(types.StringType),
+		Timeouts:       m.Timeouts,
+	}
+	if typ := state.variantType(); state.Type.IsNull() && typ != "" {
+		state.Type = types.StringValue(typ)
+	}
+	return state
+}
+
+// valueResourceSchemaV0 is the edge_value schema before variants became a map.
+func valueResourceSchemaV0(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Computed ID.",
+				Computed:    true,
+			},
+			"value_id": schema.StringAttribute{
+				Description: "The ID of this Value.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"enabled": schema.BoolAttribute{
+				Required: true,
+			},
+			"default_variant": schema.StringAttribute{
+				Required: true,
+			},
+			"type": schema.StringAttribute{
+				Description: "The type of every variant of this Value. One of `boolean`, `string`, `json` or `integer`. Inferred from the variant blocks when omitted. Changing it forces a new Value.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"boolean_value": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"variant": schema.StringAttribute{
+							Required: true,
+						},
+						"value": schema.BoolAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+			"string_value": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"variant": schema.StringAttribute{
+							Required: true,
+						},
+						"value": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+			"json_value": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"variant": schema.StringAttribute{
+							Required: true,
+						},
+						"value": schema.StringAttribute{
+							Required: true,
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"transform": schema.ListNestedBlock{
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"spec": schema.StringAttribute{
+										Optional: true,
+									},
+									"expr": schema.StringAttribute{
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"integer_value": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"variant": schema.StringAttribute{
+							Required: true,
+						},
+						"value": schema.Int64Attribute{
+							Required: true,
+						},
+					},
+				},
+			},
+			"targeting": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"variant": schema.StringAttribute{
+							Required: true,
+						},
+						"spec": schema.StringAttribute{
+							Optional: true,
+						},
+						"expr": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+			"test": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"variables": schema.StringAttribute{
+							CustomType: jsontypes.NormalizedType{},
+							Required:   true,
+						},
+						"expected": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
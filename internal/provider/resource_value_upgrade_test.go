@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValueResourceModelV0_Upgrade(t *testing.T) {
+	t.Parallel()
+	transforms := []valueResourceTransformModel{
+		{Spec: types.StringValue("cel"), Expr: types.StringValue("{}")},
+	}
+	prior := &valueResourceModelV0{
+		ID:             types.StringValue("test"),
+		ValueID:        types.StringValue("test"),
+		DefaultVariant: types.StringValue("off"),
+		Type:           types.StringNull(),
+		BooleanValue: []valueResourceBooleanValueModel{
+			{Variant: types.StringValue("on"), Value: types.BoolValue(true)},
+			{Variant: types.StringValue("off"), Value: types.BoolValue(false)},
+		},
+		JSONValue: []valueResourceJSONValueModel{
+			{Variant: types.StringValue("json"), Value: types.StringValue("{}"), Transform: transforms},
+			{Variant: types.StringValue("empty"), Value: types.StringValue("{}"), Transform: []valueResourceTransformModel{}},
+		},
+		Targeting: []valueResourceTargetingModel{},
+		Test:      []valueResourceTestModel{},
+		Timeouts:  nullTimeouts(),
+	}
+
+	got := prior.upgrade()
+	want := map[string]valueResourceVariantModel{
+		"on":    {BooleanValue: types.BoolValue(true)},
+		"off":   {BooleanValue: types.BoolValue(false)},
+		"json":  {JSONValue: jsontypes.NewNormalizedValue("{}"), Transform: transforms},
+		"empty": {JSONValue: jsontypes.NewNormalizedValue("{}")},
+	}
+	if !reflect.DeepEqual(got.Variants, want) {
+		t.Fatalf("expected %v, but got %v", want, got.Variants)
+	}
+	if !got.Type.IsNull() {
+		t.Fatalf("expected null type for mixed variants, but got %s", got.Type)
+	}
+
+	prior.JSONValue = nil
+	if got := prior.upgrade(); got.Type.ValueString() != "boolean" {
+		t.Fatalf("expected inferred type %q, but got %s", "boolean", got.Type)
+	}
+}
@@ -0,0 +1,322 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ca-irvine/terraform-provider-edge/internal/evaluation"
+	"github.com/ca-irvine/terraform-provider-edge/internal/expr"
+	"github.com/ca-irvine/terraform-provider-edge/internal/jsontypes"
+	"github.com/ca-irvine/terraform-provider-edge/internal/model"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithValidateConfig = &ValueResource{}
+
+func (v *ValueResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateVariantValues(ctx, req.Config, &resp.Diagnostics)
+	validateTargeting(ctx, req.Config, &resp.Diagnostics)
+	validateTransforms(ctx, req.Config, "json_value", &resp.Diagnostics)
+
+	// Variants and targeting managed elsewhere are not known, so checks relying on them are
+	// skipped.
+	managed, known := validateManagedFields(ctx, req.Config, &resp.Diagnostics)
+	if !known || !managesField(managed, managedFieldVariants) {
+		return
+	}
+	validateVariantReferences(ctx, req.Config, &resp.Diagnostics)
+	validateValueType(ctx, req.Config, &resp.Diagnostics)
+
+	if !resp.Diagnostics.HasError() && managesField(managed, managedFieldTargeting) {
+		runValueTests(ctx, req.Config, &resp.Diagnostics)
+	}
+}
+
+// validateVariantValues checks that variant_values is not set along with variants, and that it
+// declares a value for every variant.
+func validateVariantValues(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	values := configVariantValues(ctx, config)
+	if values.IsNull() {
+		return
+	}
+	var variants types.Map
+	if d := config.GetAttribute(ctx, path.Root("variants"), &variants); !d.HasError() && !variants.IsNull() {
+		diags.AddAttributeError(
+			path.Root("variant_values"),
+			"Conflicting Attributes",
+			"Only one of variants and variant_values can be set.",
+		)
+		return
+	}
+	var typ types.String
+	if d := config.GetAttribute(ctx, path.Root("type"), &typ); d.HasError() || typ.IsUnknown() {
+		return
+	}
+	if _, _, err := expandVariantValues(ctx, values, typ.ValueString()); err != nil {
+		diags.AddAttributeError(path.Root("variant_values"), "Invalid Variant Values", err.Error())
+	}
+}
+
+// configVariantValues returns variant_values from config, or null for configs that have no
+// variant_values, such as those of the typed resources.
+func configVariantValues(ctx context.Context, config tfsdk.Config) types.Dynamic {
+	var values types.Dynamic
+	if d := config.GetAttribute(ctx, path.Root("variant_values"), &values); d.HasError() {
+		return types.DynamicNull()
+	}
+	return values
+}
+
+// validateTargeting checks that every targeting expression compiles for its spec.
+func validateTargeting(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var targeting []valueResourceTargetingModel
+	if configElements(ctx, config, path.Root("targeting"), &targeting) {
+		for i, t := range targeting {
+			validateTargetingExpr(path.Root("targeting").AtListIndex(i).AtName("expr"), t.Spec, t.Expr, diags)
+		}
+	}
+}
+
+// validateTransforms checks that the transform expressions of every variant compile against
+// the JSON document held in the variant attribute named valueAttr.
+func validateTransforms(ctx context.Context, config tfsdk.Config, valueAttr string, diags *diag.Diagnostics) {
+	var variants types.Map
+	if d := config.GetAttribute(ctx, path.Root("variants"), &variants); d.HasError() {
+		return
+	}
+	for k := range variants.Elements() {
+		p := path.Root("variants").AtMapKey(k).AtName("transform")
+		var transforms []valueResourceTransformModel
+		if !configElements(ctx, config, p, &transforms) {
+			continue
+		}
+		doc := configDocument(ctx, config, path.Root("variants").AtMapKey(k).AtName(valueAttr))
+		for i, t := range transforms {
+			validateTransformExpr(p.AtListIndex(i).AtName("expr"), t.Spec, t.Expr, doc, diags)
+		}
+	}
+}
+
+// configDocument decodes the JSON document at p. It returns nil when the document is not known
+// yet or is not valid JSON, which the attribute type reports on its own.
+func configDocument(ctx context.Context, config tfsdk.Config, p path.Path) any {
+	var s jsontypes.Normalized
+	if d := config.GetAttribute(ctx, p, &s); d.HasError() || s.IsNull() || s.IsUnknown() {
+		return nil
+	}
+	doc, err := jsontypes.Decode(s.ValueString())
+	if err != nil {
+		return nil
+	}
+	return doc
+}
+
+// validateVariantReferences checks that default_variant, targeting and tests only refer to
+// declared variants.
+func validateVariantReferences(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	declared, known := configVariantNames(ctx, config)
+	if !known {
+		return
+	}
+
+	checkReference := func(name types.String, p path.Path, what string) {
+		if name.IsNull() || name.IsUnknown() {
+			return
+		}
+		if _, ok := declared[name.ValueString()]; !ok {
+			diags.AddAttributeError(
+				p,
+				"Undeclared Variant",
+				fmt.Sprintf("The %s refers to variant %q, which is not declared by this value.", what, name.ValueString()),
+			)
+		}
+	}
+
+	var defaultVariant types.String
+	if d := config.GetAttribute(ctx, path.Root("default_variant"), &defaultVariant); !d.HasError() {
+		checkReference(defaultVariant, path.Root("default_variant"), "default_variant")
+	}
+
+	var targeting []valueResourceTargetingModel
+	if configElements(ctx, config, path.Root("targeting"), &targeting) {
+		for i, t := range targeting {
+			checkReference(t.Variant, path.Root("targeting").AtListIndex(i).AtName("variant"), "targeting rule")
+		}
+	}
+
+	var tests []valueResourceTestModel
+	if configElements(ctx, config, path.Root("test"), &tests) {
+		for i, t := range tests {
+			checkReference(t.Expected, path.Root("test").AtListIndex(i).AtName("expected"), "test")
+		}
+	}
+}
+
+// configVariantNames returns the names of the variants declared in config by variants or
+// variant_values. It reports false when they are not known yet.
+func configVariantNames(ctx context.Context, config tfsdk.Config) (map[string]struct{}, bool) {
+	if values := configVariantValues(ctx, config); !values.IsNull() {
+		typs, known := configVariantTypes(ctx, config)
+		if !known {
+			return nil, false
+		}
+		names := make(map[string]struct{}, len(typs))
+		for k := range typs {
+			names[k] = struct{}{}
+		}
+		return names, true
+	}
+
+	var variants types.Map
+	if d := config.GetAttribute(ctx, path.Root("variants"), &variants); d.HasError() || variants.IsNull() || variants.IsUnknown() {
+		return nil, false
+	}
+	names := make(map[string]struct{}, len(variants.Elements()))
+	for k := range variants.Elements() {
+		names[k] = struct{}{}
+	}
+	return names, true
+}
+
+// runValueTests evaluates the test blocks against the targeting rules of the configuration.
+// It does nothing until every input is known.
+func runValueTests(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var tests []valueResourceTestModel
+	if !configElements(ctx, config, path.Root("test"), &tests) || len(tests) == 0 {
+		return
+	}
+	var defaultVariant types.String
+	if d := config.GetAttribute(ctx, path.Root("default_variant"), &defaultVariant); d.HasError() || defaultVariant.IsUnknown() {
+		return
+	}
+	var targeting []valueResourceTargetingModel
+	if !configElements(ctx, config, path.Root("targeting"), &targeting) {
+		return
+	}
+
+	value := &model.Value{
+		DefaultVariant: defaultVariant.ValueString(),
+		Targeting: model.ValueTargeting{
+			Rules: make([]model.ValueTargetingRule, 0, len(targeting)),
+		},
+		Tests: make([]*model.EvaluationTest, 0, len(tests)),
+	}
+	for _, t := range targeting {
+		if t.Variant.IsUnknown() || t.Spec.IsUnknown() || t.Expr.IsUnknown() {
+			return
+		}
+		value.Targeting.Rules = append(value.Targeting.Rules, model.ValueTargetingRule{
+			Variant: t.Variant.ValueString(),
+			Spec:    model.ValueTargetingRuleSpecFrom(t.Spec.ValueString()),
+			Expr:    t.Expr.ValueString(),
+		})
+	}
+	for i, t := range tests {
+		if t.Variables.IsUnknown() || t.Expected.IsUnknown() {
+			return
+		}
+		vars, err := evaluation.DecodeVariables(t.Variables.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("test").AtListIndex(i).AtName("variables"),
+				"Invalid Test Variables",
+				"The test variables must be a JSON object: "+err.Error(),
+			)
+			return
+		}
+		value.Tests = append(value.Tests, &model.EvaluationTest{
+			Variables: vars,
+			Expected:  t.Expected.ValueString(),
+		})
+	}
+
+	failures, err := evaluation.RunTests(value)
+	if errors.Is(err, evaluation.ErrUnsupported) {
+		diags.AddAttributeWarning(
+			path.Root("test"),
+			"Tests Not Evaluated Locally",
+			"The tests are only checked by the Edge API because some targeting rules do not use the cel spec.",
+		)
+		return
+	}
+	if err != nil {
+		diags.AddError("Error running value tests", err.Error())
+		return
+	}
+	for _, f := range failures {
+		diags.AddAttributeError(
+			path.Root("test").AtListIndex(f.Index).AtName("expected"),
+			"Value Test Failed",
+			testFailureDetail(value, f),
+		)
+	}
+}
+
+func testFailureDetail(value *model.Value, f *evaluation.Failure) string {
+	var b strings.Builder
+	if f.Result.Rule < 0 {
+		fmt.Fprintf(&b, "test[%d] expected variant %q, but no targeting rule matched and default_variant %q was used.",
+			f.Index, f.Test.Expected, f.Result.Variant)
+	} else {
+		fmt.Fprintf(&b, "test[%d] expected variant %q, but targeting[%d] (%s) matched and produced variant %q.",
+			f.Index, f.Test.Expected, f.Result.Rule, value.Targeting.Rules[f.Result.Rule].Expr, f.Result.Variant)
+	}
+	for i := range value.Targeting.Rules {
+		if err, ok := f.Result.Errors[i]; ok {
+			fmt.Fprintf(&b, "\n\ntargeting[%d] was skipped because it failed to evaluate: %s", i, err)
+		}
+	}
+	return b.String()
+}
+
+// configElements decodes the list at p into target. It reports false when the list is not fully
+// known yet, in which case validation is left to a later phase.
+func configElements[T any](ctx context.Context, config tfsdk.Config, p path.Path, target *[]T) bool {
+	var list types.List
+	if diags := config.GetAttribute(ctx, p, &list); diags.HasError() {
+		return false
+	}
+	if list.IsNull() {
+		return true
+	}
+	if list.IsUnknown() {
+		return false
+	}
+	diags := list.ElementsAs(ctx, target, false)
+	return !diags.HasError()
+}
+
+func validateTargetingExpr(p path.Path, spec, src types.String, diags *diag.Diagnostics) {
+	if spec.IsUnknown() || src.IsNull() || src.IsUnknown() {
+		return
+	}
+	switch model.ValueTargetingRuleSpecFrom(spec.ValueString()) {
+	case model.ValueTargetingRuleSpecJsonLogic:
+		if !json.Valid([]byte(src.ValueString())) {
+			diags.AddAttributeError(p, "Invalid JsonLogic Expression", "The targeting expression is not valid JSON.")
+		}
+	case model.ValueTargetingRuleSpecCEL:
+		if err := expr.CheckTargeting(src.ValueString()); err != nil {
+			diags.AddAttributeError(p, "Invalid CEL Expression", "The targeting expression does not compile:\n\n"+err.Error())
+		}
+	}
+}
+
+func validateTransformExpr(p path.Path, spec, src types.String, doc any, diags *diag.Diagnostics) {
+	if spec.IsUnknown() || src.IsNull() || src.IsUnknown() {
+		return
+	}
+	if model.ValueTransformSpecFrom(spec.ValueString()) != model.ValueTransformSpecCEL {
+		return
+	}
+	if err := expr.CheckTransform(src.ValueString(), doc); err != nil {
+		diags.AddAttributeError(p, "Invalid CEL Expression", "The transform expression does not compile:\n\n"+err.Error())
+	}
+}
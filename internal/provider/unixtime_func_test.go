@@ -11,9 +11,8 @@ import (
 func Test_UnixTimeConverterFunc(t *testing.T) {
 	t.Parallel()
 
-	cfg := &config{}
 	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: protoV6ProviderFactories(cfg),
+		ProtoV6ProviderFactories: protoV6ProviderFactories(nil),
 		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
 			tfversion.SkipBelow(version.Must(version.NewVersion("1.8.0"))),
 		},